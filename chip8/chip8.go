@@ -1,12 +1,19 @@
 package chip8
 
 import (
+	"context"
 	"errors"
-	"fmt"
-	"io/ioutil"
-	"math/rand"
+	"time"
 )
 
+// defaultClockHz is the CPU tick rate used when a Chip8 is constructed
+// without an explicit clock speed.
+const defaultClockHz = 500
+
+// timerHz is the rate at which the delay and sound timers count down,
+// fixed by the original COSMAC VIP implementation.
+const timerHz = 60
+
 // Chip8 is the struct used for emulation
 type Chip8 struct {
 	memory [4096]byte
@@ -26,8 +33,45 @@ type Chip8 struct {
 
 	drawFlag bool
 
-	gfx [64 * 32]byte
+	fb  *framebuffer
 	key [16]byte
+
+	// plane is the XO-CHIP drawing/scroll plane bitmask selected by
+	// Fx01 (bit 0 = plane 0, bit 1 = plane 1); 0 behaves as plane 0.
+	plane byte
+
+	rpl          [8]byte
+	audioPattern [16]byte
+	pitch        byte
+
+	quirks Quirks
+
+	// exited is set by the SCHIP/XO-CHIP 00FD opcode and observed by Run.
+	exited bool
+
+	// vblankReady is set on every 60Hz timer tick and cleared by a draw;
+	// under Quirks.DisplayWait, DXYN stalls until it is true again.
+	vblankReady bool
+
+	display  Display
+	keyboard Keyboard
+	buzzer   Buzzer
+	rand     RandSource
+
+	clockHz int
+
+	trapHandler TrapHandler
+}
+
+// TrapHandler is notified whenever Run's call to EmulateCycle returns a
+// *TrapError, instead of Run tearing down the loop. It's the hook a
+// frontend uses to pause and open a debugger at the point of the trap.
+type TrapHandler func(*TrapError)
+
+// SetTrapHandler installs h to be called whenever Run hits a trap. If no
+// handler is installed, Run instead returns the *TrapError and stops.
+func (c8 *Chip8) SetTrapHandler(h TrapHandler) {
+	c8.trapHandler = h
 }
 
 var fontSet = [80]byte{
@@ -49,77 +93,138 @@ var fontSet = [80]byte{
 	0xF0, 0x80, 0xF0, 0x80, 0x80, // F
 }
 
-const maxRomSize = 0xFFF - 0x200
+// Run drives the CPU at the configured clock speed and decrements the
+// delay/sound timers at a fixed 60Hz, until ctx is cancelled, the ROM
+// executes 00FD, or a cycle returns an error that no TrapHandler takes.
+func (c8 *Chip8) Run(ctx context.Context) error {
+	hz := c8.clockHz
+	if hz <= 0 {
+		hz = defaultClockHz
+	}
 
-// ErrRomTooLarge is thrown if the read in ROM size is larger than the max
-var ErrRomTooLarge = errors.New("rom size is too large")
+	cpuTicker := time.NewTicker(time.Second / time.Duration(hz))
+	defer cpuTicker.Stop()
+
+	timerTicker := time.NewTicker(time.Second / timerHz)
+	defer timerTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timerTicker.C:
+			c8.tickTimers()
+		case <-cpuTicker.C:
+			if err := c8.emulateCycle(ctx); err != nil {
+				var trapErr *TrapError
+				if errors.As(err, &trapErr) && c8.trapHandler != nil {
+					c8.trapHandler(trapErr)
+					continue
+				}
+				return err
+			}
+			if c8.drawFlag {
+				c8.present()
+			}
+			if c8.exited {
+				return nil
+			}
+		}
+	}
+}
 
-// New Loads a new version of Chip8 with fonts and the rom
-func New(filepath string) (*Chip8, error) {
-	// rand.Seed(time.Now().UnixNano())
+// tickTimers decrements the delay and sound timers at 60Hz and stops the
+// buzzer when the sound timer reaches zero; FX18 starts it.
+func (c8 *Chip8) tickTimers() {
+	c8.vblankReady = true
 
-	c8 := Chip8{
-		pc: 0x200,
+	if c8.delayTimer > 0 {
+		c8.delayTimer--
 	}
-	c8.loadFontset()
-	err := c8.loadROM(filepath)
 
-	if err != nil {
-		return nil, err
+	if c8.soundTimer > 0 {
+		c8.soundTimer--
+		if c8.soundTimer == 0 && c8.buzzer != nil {
+			c8.buzzer.Stop()
+		}
 	}
-
-	return &c8, nil
 }
 
-func (c8 *Chip8) loadFontset() {
-	for i := 0; i < 80; i++ {
-		c8.memory[0] = fontSet[i]
+// present pushes the framebuffer to the configured Display.
+func (c8 *Chip8) present() {
+	if c8.display == nil {
+		return
 	}
-}
-
-func (c8 *Chip8) loadROM(filepath string) error {
-	rom, err := ioutil.ReadFile(filepath)
-
-	if err != nil {
-		return err
+	for y := 0; y < c8.fb.height; y++ {
+		for x := 0; x < c8.fb.width; x++ {
+			c8.display.SetPixel(x, y, c8.fb.colorAt(x, y) != 0)
+		}
 	}
+	c8.display.Refresh()
+}
 
-	if len(rom) > maxRomSize {
-		return ErrRomTooLarge
+// setHiRes toggles the framebuffer between 64x32 and 128x64 and, if a
+// Display is attached, notifies it of the new dimensions so a frontend
+// sized for the classic resolution can resize before the next present.
+func (c8 *Chip8) setHiRes(hi bool) {
+	if hi == c8.fb.hiRes() {
+		return
 	}
-	for i := 0; i < len(rom); i++ {
-		c8.memory[0x200+i] = rom[i]
+	c8.fb.setHiRes(hi)
+	if c8.display != nil {
+		c8.display.Resize(c8.fb.width, c8.fb.height)
 	}
-	return nil
 }
 
-// todo: understand what this is doing
-func (c8 *Chip8) drawSprite(x, y, sprite uint16) {
-	c8.v[0xF] = 0
-	var pix uint16
-
-	for yLine := uint16(0); yLine < sprite; yLine++ {
-		pix = uint16(c8.memory[c8.i+yLine])
+func (c8 *Chip8) loadFontset() {
+	for i := 0; i < 80; i++ {
+		c8.memory[i] = fontSet[i]
+	}
+}
 
-		for xLine := uint16(0); xLine < 8; xLine++ {
-			idx := (x + xLine + ((y + yLine) * 64))
-			if idx >= uint16(len(c8.gfx)) {
-				continue
-			}
-			if (pix & (0x80 >> xLine)) != 0 {
-				if c8.gfx[idx] == 1 {
-					c8.v[0xF] = 1
-				}
-				c8.gfx[idx] ^= 1
-			}
+// pollKeys refreshes the internal key matrix from the Keyboard, if one
+// is attached.
+func (c8 *Chip8) pollKeys() {
+	if c8.keyboard == nil {
+		return
+	}
+	for k := byte(0); k < 16; k++ {
+		if c8.keyboard.IsDown(k) {
+			c8.key[k] = 1
+		} else {
+			c8.key[k] = 0
 		}
 	}
-	c8.drawFlag = true
 }
 
-func (c8 *Chip8) emulateCycle() {
+// EmulateCycle fetches, decodes and executes a single instruction. It
+// returns a *TrapError instead of panicking or printing to stdout when
+// it hits bad memory/stack access, an unknown opcode, or a malformed
+// sprite, so a frontend can pause and open a debugger on it.
+//
+// Called outside of Run (e.g. by a Debugger single-stepping), FX0A waits
+// on the Keyboard with a background context and so blocks until a key
+// arrives; use Run when cancellation needs to reach a pending FX0A.
+func (c8 *Chip8) EmulateCycle() error {
+	return c8.emulateCycle(context.Background())
+}
+
+// emulateCycle is EmulateCycle with ctx threaded through to FX0A's
+// Keyboard.WaitKey, so Run can cancel a pending "wait for key" without
+// blocking the select loop that also drives the timers.
+func (c8 *Chip8) emulateCycle(ctx context.Context) error {
 	c8.drawFlag = false
-	c8.opcode = uint16(c8.memory[c8.pc])<<8 | uint16(c8.memory[c8.pc+1])
+	c8.pollKeys()
+
+	hi, err := c8.readMem(c8.pc)
+	if err != nil {
+		return err
+	}
+	lo, err := c8.readMem(c8.pc + 1)
+	if err != nil {
+		return err
+	}
+	c8.opcode = uint16(hi)<<8 | uint16(lo)
 
 	x := (c8.opcode & 0x0F00) >> 8
 	y := (c8.opcode & 0x00F0) >> 4
@@ -133,20 +238,27 @@ func (c8 *Chip8) emulateCycle() {
 		break
 
 	case 0xB000:
-		c8.pc = nnn + uint16(c8.v[0])
+		if c8.quirks.JumpOffsetVX {
+			c8.pc = nnn + uint16(c8.v[x])
+		} else {
+			c8.pc = nnn + uint16(c8.v[0])
+		}
 		c8.pc += 2
 		break
 
 	case 0xC000:
-		c8.v[x] = byte(rand.Intn(255)) & nn
+		c8.v[x] = byte(c8.rand.Intn(255)) & nn
 		c8.pc += 2
 		break
 
 	case 0xD000:
-
-		x = uint16(c8.v[x])
-		y = uint16(c8.v[y])
-		c8.drawSprite(x, y, c8.opcode&0x000F)
+		if c8.quirks.DisplayWait && !c8.vblankReady {
+			return nil
+		}
+		c8.vblankReady = false
+		if err := c8.drawSprite(c8.v[x], c8.v[y], byte(c8.opcode&0x000F)); err != nil {
+			return err
+		}
 		c8.pc += 2
 		break
 
@@ -165,6 +277,8 @@ func (c8 *Chip8) emulateCycle() {
 			}
 			c8.pc += 2
 			break
+		default:
+			return c8.trap(TrapUnknownOpcode, "")
 		}
 
 	case 0xF000:
@@ -174,6 +288,15 @@ func (c8 *Chip8) emulateCycle() {
 			c8.pc += 2
 			break
 		case 0x000A:
+			if c8.keyboard != nil {
+				key, err := c8.keyboard.WaitKey(ctx)
+				if err != nil {
+					return err
+				}
+				c8.v[x] = key
+				c8.pc += 2
+				break
+			}
 			for i, k := range c8.key {
 				if k != 0 {
 					c8.v[x] = byte(i)
@@ -181,7 +304,6 @@ func (c8 *Chip8) emulateCycle() {
 					break
 				}
 			}
-			c8.key[c8.v[x]] = 0
 			break
 		case 0x0015:
 			c8.delayTimer = c8.v[x]
@@ -189,6 +311,9 @@ func (c8 *Chip8) emulateCycle() {
 			break
 		case 0x0018:
 			c8.soundTimer = c8.v[x]
+			if c8.soundTimer > 0 && c8.buzzer != nil {
+				c8.buzzer.Start()
+			}
 			c8.pc += 2
 			break
 		case 0x001E:
@@ -200,48 +325,129 @@ func (c8 *Chip8) emulateCycle() {
 			c8.pc += 2
 			break
 		case 0x0033:
-			c8.memory[c8.i] = c8.v[x] / 100
-			c8.memory[c8.i+1] = (c8.v[x] / 10) % 10
-			c8.memory[c8.i+2] = (c8.v[x] / 100) % 10
+			if err := c8.writeMem(c8.i, c8.v[x]/100); err != nil {
+				return err
+			}
+			if err := c8.writeMem(c8.i+1, (c8.v[x]/10)%10); err != nil {
+				return err
+			}
+			if err := c8.writeMem(c8.i+2, c8.v[x]%10); err != nil {
+				return err
+			}
 			c8.pc += 2
 			break
 		case 0x0055:
 			for idx := uint16(0); idx <= x; idx++ {
-				c8.memory[c8.i+idx] = c8.v[idx]
+				if err := c8.writeMem(c8.i+idx, c8.v[idx]); err != nil {
+					return err
+				}
+			}
+			if !c8.quirks.LoadStoreLeavesI {
+				c8.i += x + 1
 			}
 			c8.pc += 2
 			break
 		case 0x0065:
 			for idx := uint16(0); idx <= x; idx++ {
-				c8.v[idx] = c8.memory[c8.i+idx]
+				v, err := c8.readMem(c8.i + idx)
+				if err != nil {
+					return err
+				}
+				c8.v[idx] = v
+			}
+			if !c8.quirks.LoadStoreLeavesI {
+				c8.i += x + 1
+			}
+			c8.pc += 2
+			break
+		case 0x0000: // XO-CHIP F000 NNNN: load I with the following 16-bit address
+			hi, err := c8.readMem(c8.pc + 2)
+			if err != nil {
+				return err
+			}
+			lo, err := c8.readMem(c8.pc + 3)
+			if err != nil {
+				return err
+			}
+			c8.i = uint16(hi)<<8 | uint16(lo)
+			c8.pc += 4
+			break
+		case 0x0001: // XO-CHIP FX01: select drawing/scrolling plane(s)
+			c8.plane = byte(x)
+			c8.pc += 2
+			break
+		case 0x0002: // XO-CHIP FX02: load the audio pattern buffer from I
+			if err := c8.loadAudioPattern(); err != nil {
+				return err
 			}
 			c8.pc += 2
 			break
+		case 0x0030: // SCHIP FX30: point I at the large hex digit font
+			c8.i = bigFontBase + uint16(c8.v[x])*10
+			c8.pc += 2
+			break
+		case 0x003A: // XO-CHIP FX3A: set the audio playback pitch
+			c8.pitch = c8.v[x]
+			c8.pc += 2
+			break
+		case 0x0075: // SCHIP FX75: save V0..VX to the RPL flags
+			c8.saveRPL(byte(x))
+			c8.pc += 2
+			break
+		case 0x0085: // SCHIP FX85: load V0..VX from the RPL flags
+			c8.loadRPL(byte(x))
+			c8.pc += 2
+			break
+		default:
+			return c8.trap(TrapUnknownOpcode, "")
 		}
 
 	case 0x0000:
-		switch c8.opcode & 0x00FF {
-		case 0x00E0: //clears the screen
-			for i := 0; i < 2048; i++ {
-				c8.gfx[i] = 0x0
+		switch {
+		case c8.opcode&0x00F0 == 0x00C0: // SCHIP 00Cn: scroll display down n pixels
+			c8.scroll00Cn(byte(c8.opcode & 0x000F))
+			c8.drawFlag = true
+			c8.pc += 2
+		case c8.opcode&0x00F0 == 0x00D0: // XO-CHIP 00Dn: scroll display up n pixels
+			c8.scroll00Dn(byte(c8.opcode & 0x000F))
+			c8.drawFlag = true
+			c8.pc += 2
+		case c8.opcode == 0x00E0: //clears the screen
+			c8.fb.clear(c8.planeOrDefault())
+			c8.drawFlag = true
+			c8.pc += 2
+		case c8.opcode == 0x00EE:
+			ret, err := c8.popStack()
+			if err != nil {
+				return err
 			}
+			c8.pc = ret + 2
+		case c8.opcode == 0x00FB: // SCHIP 00FB: scroll display right 4 pixels
+			c8.scroll00FB()
 			c8.drawFlag = true
 			c8.pc += 2
-			break
-		case 0x00EE:
-			c8.pc = c8.stack[c8.sp] + 2
-			c8.sp--
-			break
+		case c8.opcode == 0x00FC: // SCHIP 00FC: scroll display left 4 pixels
+			c8.scroll00FC()
+			c8.drawFlag = true
+			c8.pc += 2
+		case c8.opcode == 0x00FD: // SCHIP/XO-CHIP 00FD: exit the interpreter
+			c8.exit00FD()
+		case c8.opcode == 0x00FE: // SCHIP 00FE: switch to low-res (64x32)
+			c8.setHiRes(false)
+			c8.pc += 2
+		case c8.opcode == 0x00FF: // SCHIP 00FF: switch to hi-res (128x64)
+			c8.setHiRes(true)
+			c8.pc += 2
 		default:
-			fmt.Printf("Unknown opcode [0x0000]: 0x%X\n", c8.opcode)
-			break
+			return c8.trap(TrapUnknownOpcode, "")
 		}
 	case 0x1000: // Jumps to address NNN
 		c8.pc = nnn
 		break
 	case 0x2000: // Calls subroutine at NNN
-		c8.sp++
-		c8.stack[c8.sp] = c8.pc
+		if err := c8.pushStack(c8.pc); err != nil {
+			return err
+		}
 		c8.pc = nnn
 		break
 	case 0x3000:
@@ -257,10 +463,26 @@ func (c8 *Chip8) emulateCycle() {
 		c8.pc += 2
 		break
 	case 0x5000:
-		if c8.v[x] == c8.v[y] {
+		switch c8.opcode & 0x000F {
+		case 0x0002: // XO-CHIP: save VX..VY to memory at I
+			if err := c8.saveRange(byte(x), byte(y)); err != nil {
+				return err
+			}
 			c8.pc += 2
+			break
+		case 0x0003: // XO-CHIP: load VX..VY from memory at I
+			if err := c8.loadRange(byte(x), byte(y)); err != nil {
+				return err
+			}
+			c8.pc += 2
+			break
+		default:
+			if c8.v[x] == c8.v[y] {
+				c8.pc += 2
+			}
+			c8.pc += 2
+			break
 		}
-		c8.pc += 2
 		break
 	case 0x6000:
 		c8.v[x] = nn
@@ -307,9 +529,12 @@ func (c8 *Chip8) emulateCycle() {
 			c8.pc += 2
 			break
 		case 0x0006:
-			vx := c8.v[x]
-			c8.v[0xF] = vx & 0x1
-			c8.v[x] = vx >> 1
+			src := c8.v[x]
+			if !c8.quirks.ShiftUsesVX {
+				src = c8.v[y]
+			}
+			c8.v[0xF] = src & 0x1
+			c8.v[x] = src >> 1
 			c8.pc += 2
 			break
 		case 0x0007:
@@ -322,10 +547,16 @@ func (c8 *Chip8) emulateCycle() {
 			c8.pc += 2
 			break
 		case 0x000E:
-			c8.v[0xF] = (c8.v[x] & 0x80) >> 7
-			c8.v[x] = (c8.v[x] << 1) & 0xFF
+			src := c8.v[x]
+			if !c8.quirks.ShiftUsesVX {
+				src = c8.v[y]
+			}
+			c8.v[0xF] = (src & 0x80) >> 7
+			c8.v[x] = (src << 1) & 0xFF
 			c8.pc += 2
 			break
+		default:
+			return c8.trap(TrapUnknownOpcode, "")
 		}
 	case 0x9000:
 		switch c8.opcode & 0x000F {
@@ -335,16 +566,12 @@ func (c8 *Chip8) emulateCycle() {
 			}
 			c8.pc += 2
 			break
+		default:
+			return c8.trap(TrapUnknownOpcode, "")
 		}
 	default:
-		fmt.Printf("Unknown opcode: 0x%X\n", c8.opcode)
+		return c8.trap(TrapUnknownOpcode, "")
 	}
 
-	// if c8.delayTimer > 0 {
-	// 	c8.delayTimer--
-	// }
-
-	// if c8.soundTimer > 0 {
-	// 	c8.soundTimer--
-	// }
+	return nil
 }
@@ -0,0 +1,216 @@
+package debug
+
+import (
+	"fmt"
+
+	"github.com/nickzelei/go-chip8/chip8"
+)
+
+// historyDepth bounds how many past states ReverseStep can rewind
+// through.
+const historyDepth = 256
+
+// OpcodeMatcher reports whether an about-to-execute opcode should trip
+// a breakpoint, e.g. func(op uint16) bool { return op&0xF000 == 0xD000 }
+// to break on every draw.
+type OpcodeMatcher func(opcode uint16) bool
+
+// Watchpoint fires whenever the value at a memory range or V register
+// changes between two Steps.
+type Watchpoint struct {
+	Name string
+
+	MemStart, MemEnd uint16
+	Register         int
+	IsRegister       bool
+
+	last []byte
+}
+
+// Debugger wraps a *chip8.Chip8 with breakpoints, watchpoints,
+// single-stepping and reverse-stepping, so a ROM author can see exactly
+// why a cycle did what it did instead of guessing from garbled output.
+type Debugger struct {
+	core *chip8.Chip8
+
+	pcBreakpoints map[uint16]bool
+	opBreakpoints []OpcodeMatcher
+	watchpoints   []*Watchpoint
+
+	// history is a ring buffer of Snapshot blobs, oldest first, used by
+	// ReverseStep.
+	history [][]byte
+}
+
+// NewDebugger wraps core for debugging.
+func NewDebugger(core *chip8.Chip8) *Debugger {
+	return &Debugger{
+		core:          core,
+		pcBreakpoints: make(map[uint16]bool),
+	}
+}
+
+// BreakAtPC sets a breakpoint at addr.
+func (d *Debugger) BreakAtPC(addr uint16) {
+	d.pcBreakpoints[addr] = true
+}
+
+// ClearBreakAtPC removes a breakpoint previously set with BreakAtPC.
+func (d *Debugger) ClearBreakAtPC(addr uint16) {
+	delete(d.pcBreakpoints, addr)
+}
+
+// BreakOnOpcode adds a breakpoint that trips whenever match reports
+// true for the opcode about to execute.
+func (d *Debugger) BreakOnOpcode(match OpcodeMatcher) {
+	d.opBreakpoints = append(d.opBreakpoints, match)
+}
+
+// WatchMemory adds a watchpoint on the inclusive memory range [start, end].
+func (d *Debugger) WatchMemory(name string, start, end uint16) *Watchpoint {
+	wp := &Watchpoint{Name: name, MemStart: start, MemEnd: end, last: make([]byte, int(end-start)+1)}
+	d.core.MemoryRange(start, wp.last)
+	d.watchpoints = append(d.watchpoints, wp)
+	return wp
+}
+
+// WatchRegister adds a watchpoint on V register reg.
+func (d *Debugger) WatchRegister(name string, reg int) *Watchpoint {
+	wp := &Watchpoint{Name: name, Register: reg, IsRegister: true, last: []byte{d.core.V(reg)}}
+	d.watchpoints = append(d.watchpoints, wp)
+	return wp
+}
+
+func (wp *Watchpoint) checkChanged(core *chip8.Chip8) bool {
+	if wp.IsRegister {
+		v := core.V(wp.Register)
+		changed := v != wp.last[0]
+		wp.last[0] = v
+		return changed
+	}
+
+	current := make([]byte, len(wp.last))
+	core.MemoryRange(wp.MemStart, current)
+	changed := string(current) != string(wp.last)
+	wp.last = current
+	return changed
+}
+
+// Step executes a single instruction, recording history for
+// ReverseStep, and returns any watchpoints whose value changed. If the
+// instruction traps, the *chip8.TrapError is returned alongside whatever
+// watchpoints fired beforehand.
+func (d *Debugger) Step() ([]*Watchpoint, error) {
+	if snap, err := d.core.Snapshot(); err == nil {
+		d.history = append(d.history, snap)
+		if len(d.history) > historyDepth {
+			d.history = d.history[1:]
+		}
+	}
+
+	err := d.core.Step()
+
+	var fired []*Watchpoint
+	for _, wp := range d.watchpoints {
+		if wp.checkChanged(d.core) {
+			fired = append(fired, wp)
+		}
+	}
+	return fired, err
+}
+
+// ReverseStep restores the state from immediately before the previous
+// Step, keeping the core's existing peripherals wired up. It reports
+// whether history was available to rewind into.
+func (d *Debugger) ReverseStep() bool {
+	if len(d.history) == 0 {
+		return false
+	}
+	snap := d.history[len(d.history)-1]
+	d.history = d.history[:len(d.history)-1]
+
+	if err := d.core.Restore(snap); err != nil {
+		return false
+	}
+	return true
+}
+
+func (d *Debugger) peekOpcode() uint16 {
+	hi := d.core.MemoryAt(d.core.PC())
+	lo := d.core.MemoryAt(d.core.PC() + 1)
+	return uint16(hi)<<8 | uint16(lo)
+}
+
+// AtBreakpoint reports whether the instruction about to execute trips a
+// PC or opcode breakpoint.
+func (d *Debugger) AtBreakpoint() bool {
+	if d.pcBreakpoints[d.core.PC()] {
+		return true
+	}
+	opcode := d.peekOpcode()
+	for _, match := range d.opBreakpoints {
+		if match(opcode) {
+			return true
+		}
+	}
+	return false
+}
+
+// RunUntil steps until a breakpoint trips, a Step returns an error, or
+// maxSteps is reached (a safety bound for ROMs with no breakpoints set),
+// returning the number of steps taken, including a step that errored.
+func (d *Debugger) RunUntil(maxSteps int) int {
+	steps := 0
+	for steps < maxSteps && !d.AtBreakpoint() {
+		if _, err := d.Step(); err != nil {
+			steps++
+			break
+		}
+		steps++
+	}
+	return steps
+}
+
+// State is a point-in-time dump of registers and the next instruction,
+// suitable for a text UI or JSON-RPC control surface to render.
+type State struct {
+	PC, I, SP       uint16
+	V               [16]byte
+	Stack           [16]uint16
+	DelayTimer      byte
+	SoundTimer      byte
+	NextInstruction Instruction
+}
+
+// Dump captures the current State.
+func (d *Debugger) Dump() State {
+	var v [16]byte
+	for i := range v {
+		v[i] = d.core.V(i)
+	}
+
+	opcode := d.peekOpcode()
+	mnemonic, operands := decode(opcode)
+
+	return State{
+		PC:         d.core.PC(),
+		I:          d.core.I(),
+		SP:         d.core.SP(),
+		V:          v,
+		Stack:      d.core.Stack(),
+		DelayTimer: d.core.DelayTimer(),
+		SoundTimer: d.core.SoundTimer(),
+		NextInstruction: Instruction{
+			Addr:     d.core.PC(),
+			Opcode:   opcode,
+			Mnemonic: mnemonic,
+			Operands: operands,
+		},
+	}
+}
+
+// String renders State the way a text UI would print it.
+func (s State) String() string {
+	return fmt.Sprintf("PC=%04X I=%04X SP=%04X DT=%02X ST=%02X next=%s\nV=%02X",
+		s.PC, s.I, s.SP, s.DelayTimer, s.SoundTimer, s.NextInstruction, s.V)
+}
@@ -0,0 +1,181 @@
+package debug
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nickzelei/go-chip8/chip8"
+)
+
+// stubDisplay/stubKeyboard/stubBuzzer are no-op peripherals so tests can
+// construct a Chip8 without pulling in a real frontend.
+type stubDisplay struct{}
+
+func (stubDisplay) SetPixel(x, y int, on bool) {}
+func (stubDisplay) Refresh() error             { return nil }
+func (stubDisplay) Resize(width, height int)   {}
+
+type stubKeyboard struct{}
+
+func (stubKeyboard) IsDown(key byte) bool                      { return false }
+func (stubKeyboard) WaitKey(ctx context.Context) (byte, error) { return 0, nil }
+
+// countingBuzzer counts Start calls so a test can tell whether the
+// Buzzer a core was constructed with is still wired up, since Chip8
+// exposes no getter for its unexported peripheral fields.
+type countingBuzzer struct{ starts int }
+
+func (b *countingBuzzer) Start() { b.starts++ }
+func (b *countingBuzzer) Stop()  {}
+
+// TestReverseStepKeepsPeripherals guards against ReverseStep overwriting
+// the core's live display/keyboard/buzzer with the zero-value
+// peripherals a bare chip8.Load produces: if the buzzer were dropped,
+// FX18 below would silently stop ringing it.
+func TestReverseStepKeepsPeripherals(t *testing.T) {
+	rom := []byte{
+		0x60, 0x05, // 0x200: LD V0, 0x05
+		0x70, 0x01, // 0x202: ADD V0, 1
+		0xF0, 0x18, // 0x204: LD ST, V0
+	}
+
+	buzzer := &countingBuzzer{}
+	core, err := chip8.LoadFromBytes(rom, stubDisplay{}, stubKeyboard{}, buzzer, nil, chip8.WithQuirks(chip8.ProfileChip8))
+	if err != nil {
+		t.Fatalf("LoadFromBytes: %v", err)
+	}
+
+	dbg := NewDebugger(core)
+	if _, err := dbg.Step(); err != nil { // LD V0, 0x05
+		t.Fatalf("Step: %v", err)
+	}
+	if _, err := dbg.Step(); err != nil { // ADD V0, 1
+		t.Fatalf("Step: %v", err)
+	}
+
+	if !dbg.ReverseStep() { // undo ADD V0, 1
+		t.Fatalf("ReverseStep: no history to rewind into")
+	}
+
+	if _, err := dbg.Step(); err != nil { // redo ADD V0, 1
+		t.Fatalf("Step after ReverseStep: %v", err)
+	}
+	if _, err := dbg.Step(); err != nil { // LD ST, V0
+		t.Fatalf("Step: %v", err)
+	}
+
+	if buzzer.starts != 1 {
+		t.Fatalf("buzzer.starts = %d, want 1 (peripherals dropped by ReverseStep?)", buzzer.starts)
+	}
+}
+
+// loopROM counts V0 up forever: LD V0,0 / ADD V0,1 / JP 0x202.
+var loopROM = []byte{
+	0x60, 0x00, // 0x200: LD V0, 0
+	0x70, 0x01, // 0x202: ADD V0, 1
+	0x12, 0x02, // 0x204: JP 0x202
+}
+
+func newDebugger(t *testing.T, rom []byte) *Debugger {
+	t.Helper()
+	core, err := chip8.LoadFromBytes(rom, stubDisplay{}, stubKeyboard{}, stubBuzzer{}, nil, chip8.WithQuirks(chip8.ProfileChip8))
+	if err != nil {
+		t.Fatalf("LoadFromBytes: %v", err)
+	}
+	return NewDebugger(core)
+}
+
+type stubBuzzer struct{}
+
+func (stubBuzzer) Start() {}
+func (stubBuzzer) Stop()  {}
+
+func TestBreakAtPC(t *testing.T) {
+	dbg := newDebugger(t, loopROM)
+	dbg.BreakAtPC(0x204)
+
+	if dbg.AtBreakpoint() {
+		t.Fatalf("AtBreakpoint true before reaching the breakpoint")
+	}
+
+	steps := dbg.RunUntil(100)
+	if steps != 2 {
+		t.Fatalf("RunUntil stopped after %d steps, want 2", steps)
+	}
+	if dbg.core.PC() != 0x204 {
+		t.Fatalf("PC = %#x, want 0x204", dbg.core.PC())
+	}
+	if !dbg.AtBreakpoint() {
+		t.Fatalf("AtBreakpoint false at the breakpoint address")
+	}
+
+	dbg.ClearBreakAtPC(0x204)
+	if dbg.AtBreakpoint() {
+		t.Fatalf("AtBreakpoint still true after ClearBreakAtPC")
+	}
+}
+
+func TestBreakOnOpcode(t *testing.T) {
+	dbg := newDebugger(t, loopROM)
+	dbg.BreakOnOpcode(func(op uint16) bool { return op&0xF000 == 0x7000 }) // break on ADD VX, NN
+
+	steps := dbg.RunUntil(100)
+	if steps != 1 {
+		t.Fatalf("RunUntil stopped after %d steps, want 1", steps)
+	}
+	if dbg.core.PC() != 0x202 {
+		t.Fatalf("PC = %#x, want 0x202", dbg.core.PC())
+	}
+}
+
+func TestRunUntilStopsAtMaxStepsWithoutBreakpoints(t *testing.T) {
+	dbg := newDebugger(t, loopROM)
+
+	steps := dbg.RunUntil(10)
+	if steps != 10 {
+		t.Fatalf("RunUntil(10) with no breakpoints ran %d steps, want 10", steps)
+	}
+}
+
+func TestWatchRegisterFires(t *testing.T) {
+	dbg := newDebugger(t, loopROM)
+	wp := dbg.WatchRegister("V0", 0)
+
+	if _, err := dbg.Step(); err != nil { // LD V0, 0 - no change from the initial zero value
+		t.Fatalf("Step: %v", err)
+	}
+	fired, err := dbg.Step() // ADD V0, 1 - V0 changes
+	if err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+
+	if len(fired) != 1 || fired[0] != wp {
+		t.Fatalf("expected watchpoint %q to fire, got %v", wp.Name, fired)
+	}
+}
+
+func TestWatchMemoryFires(t *testing.T) {
+	rom := []byte{
+		0x60, 0x2A, // 0x200: LD V0, 0x2A
+		0xA3, 0x00, // 0x202: LD I, 0x300
+		0xF0, 0x55, // 0x204: LD [I], V0
+	}
+	dbg := newDebugger(t, rom)
+	wp := dbg.WatchMemory("mem[0x300]", 0x300, 0x300)
+
+	for i := 0; i < 2; i++ { // LD V0, 0x2A then LD I, 0x300 - memory at 0x300 untouched
+		if fired, err := dbg.Step(); err != nil {
+			t.Fatalf("Step: %v", err)
+		} else if len(fired) != 0 {
+			t.Fatalf("watchpoint fired early: %v", fired)
+		}
+	}
+
+	fired, err := dbg.Step() // LD [I], V0 - writes 0x2A to memory[0x300]
+	if err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	if len(fired) != 1 || fired[0] != wp {
+		t.Fatalf("expected watchpoint %q to fire, got %v", wp.Name, fired)
+	}
+}
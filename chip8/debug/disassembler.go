@@ -0,0 +1,185 @@
+// Package debug provides a disassembler and an interactive debugger for
+// chip8.Chip8, so ROM authors can step through execution and inspect
+// state instead of guessing at the many CHIP-8 quirks.
+package debug
+
+import "fmt"
+
+// Instruction is one decoded opcode, produced by Disassemble.
+type Instruction struct {
+	Addr     uint16
+	Opcode   uint16
+	Mnemonic string
+	Operands []string
+}
+
+// String renders the instruction the way a text UI or log line would.
+func (ins Instruction) String() string {
+	if len(ins.Operands) == 0 {
+		return fmt.Sprintf("%04X: %04X  %s", ins.Addr, ins.Opcode, ins.Mnemonic)
+	}
+	operands := ins.Operands[0]
+	for _, op := range ins.Operands[1:] {
+		operands += ", " + op
+	}
+	return fmt.Sprintf("%04X: %04X  %s %s", ins.Addr, ins.Opcode, ins.Mnemonic, operands)
+}
+
+// Disassemble decodes every two-byte opcode in rom as if it were loaded
+// at base (normally 0x200), producing one Instruction per opcode. It
+// does not attempt to distinguish code from data - sprite data decoded
+// as opcodes will simply produce nonsensical-looking instructions.
+func Disassemble(rom []byte, base uint16) []Instruction {
+	instructions := make([]Instruction, 0, len(rom)/2)
+
+	for i := 0; i+1 < len(rom); i += 2 {
+		addr := base + uint16(i)
+		opcode := uint16(rom[i])<<8 | uint16(rom[i+1])
+		mnemonic, operands := decode(opcode)
+		instructions = append(instructions, Instruction{
+			Addr:     addr,
+			Opcode:   opcode,
+			Mnemonic: mnemonic,
+			Operands: operands,
+		})
+	}
+
+	return instructions
+}
+
+func vreg(n uint16) string {
+	return fmt.Sprintf("V%X", n)
+}
+
+func hex(n uint16) string {
+	return fmt.Sprintf("0x%02X", n)
+}
+
+// decode returns the mnemonic and operand strings for a single opcode.
+func decode(opcode uint16) (string, []string) {
+	x := (opcode & 0x0F00) >> 8
+	y := (opcode & 0x00F0) >> 4
+	n := opcode & 0x000F
+	nn := opcode & 0x00FF
+	nnn := opcode & 0x0FFF
+
+	switch opcode & 0xF000 {
+	case 0x0000:
+		switch {
+		case opcode == 0x00E0:
+			return "CLS", nil
+		case opcode == 0x00EE:
+			return "RET", nil
+		case opcode == 0x00FB:
+			return "SCR", nil
+		case opcode == 0x00FC:
+			return "SCL", nil
+		case opcode == 0x00FD:
+			return "EXIT", nil
+		case opcode == 0x00FE:
+			return "LOW", nil
+		case opcode == 0x00FF:
+			return "HIGH", nil
+		case opcode&0x00F0 == 0x00C0:
+			return "SCD", []string{hex(n)}
+		case opcode&0x00F0 == 0x00D0:
+			return "SCU", []string{hex(n)}
+		}
+		return "SYS", []string{hex(nnn)}
+	case 0x1000:
+		return "JP", []string{hex(nnn)}
+	case 0x2000:
+		return "CALL", []string{hex(nnn)}
+	case 0x3000:
+		return "SE", []string{vreg(x), hex(nn)}
+	case 0x4000:
+		return "SNE", []string{vreg(x), hex(nn)}
+	case 0x5000:
+		switch n {
+		case 0x2:
+			return "SAVE", []string{vreg(x), vreg(y)}
+		case 0x3:
+			return "LOAD", []string{vreg(x), vreg(y)}
+		}
+		return "SE", []string{vreg(x), vreg(y)}
+	case 0x6000:
+		return "LD", []string{vreg(x), hex(nn)}
+	case 0x7000:
+		return "ADD", []string{vreg(x), hex(nn)}
+	case 0x8000:
+		switch n {
+		case 0x0:
+			return "LD", []string{vreg(x), vreg(y)}
+		case 0x1:
+			return "OR", []string{vreg(x), vreg(y)}
+		case 0x2:
+			return "AND", []string{vreg(x), vreg(y)}
+		case 0x3:
+			return "XOR", []string{vreg(x), vreg(y)}
+		case 0x4:
+			return "ADD", []string{vreg(x), vreg(y)}
+		case 0x5:
+			return "SUB", []string{vreg(x), vreg(y)}
+		case 0x6:
+			return "SHR", []string{vreg(x), vreg(y)}
+		case 0x7:
+			return "SUBN", []string{vreg(x), vreg(y)}
+		case 0xE:
+			return "SHL", []string{vreg(x), vreg(y)}
+		}
+	case 0x9000:
+		return "SNE", []string{vreg(x), vreg(y)}
+	case 0xA000:
+		return "LD", []string{"I", hex(nnn)}
+	case 0xB000:
+		return "JP", []string{"V0", hex(nnn)}
+	case 0xC000:
+		return "RND", []string{vreg(x), hex(nn)}
+	case 0xD000:
+		return "DRW", []string{vreg(x), vreg(y), hex(n)}
+	case 0xE000:
+		switch nn {
+		case 0x9E:
+			return "SKP", []string{vreg(x)}
+		case 0xA1:
+			return "SKNP", []string{vreg(x)}
+		}
+	case 0xF000:
+		switch nn {
+		case 0x00:
+			return "LD", []string{"I", "long"}
+		case 0x01:
+			return "PLANE", []string{hex(x)}
+		case 0x02:
+			return "LD", []string{"AUDIO", "[I]"}
+		case 0x07:
+			return "LD", []string{vreg(x), "DT"}
+		case 0x0A:
+			return "LD", []string{vreg(x), "K"}
+		case 0x15:
+			return "LD", []string{"DT", vreg(x)}
+		case 0x18:
+			return "LD", []string{"ST", vreg(x)}
+		case 0x1E:
+			return "ADD", []string{"I", vreg(x)}
+		case 0x29:
+			return "LD", []string{"F", vreg(x)}
+		case 0x30:
+			return "LD", []string{"HF", vreg(x)}
+		case 0x33:
+			return "BCD", []string{vreg(x)}
+		case 0x3A:
+			return "PITCH", []string{vreg(x)}
+		case 0x55:
+			return "LD", []string{"[I]", vreg(x)}
+		case 0x65:
+			return "LD", []string{vreg(x), "[I]"}
+		case 0x75:
+			return "LD", []string{"R", vreg(x)}
+		case 0x85:
+			return "LD", []string{vreg(x), "R"}
+		}
+	}
+
+	return "DW", []string{hex(opcode)}
+}
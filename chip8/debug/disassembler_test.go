@@ -0,0 +1,42 @@
+package debug
+
+import "testing"
+
+func TestDisassemble(t *testing.T) {
+	rom := []byte{
+		0x63, 0x2A, // LD V3, 0x2A
+		0xD0, 0x15, // DRW V0, V1, 5
+		0x00, 0xEE, // RET
+	}
+
+	instructions := Disassemble(rom, 0x200)
+	if len(instructions) != 3 {
+		t.Fatalf("expected 3 instructions, got %d", len(instructions))
+	}
+
+	want := []string{
+		"LD V3, 0x2A",
+		"DRW V0, V1, 0x05",
+		"RET",
+	}
+
+	for i, ins := range instructions {
+		got := ins.Mnemonic
+		if len(ins.Operands) > 0 {
+			got += " " + ins.Operands[0]
+			for _, op := range ins.Operands[1:] {
+				got += ", " + op
+			}
+		}
+		if got != want[i] {
+			t.Errorf("instruction %d: got %q, want %q", i, got, want[i])
+		}
+	}
+
+	if instructions[0].Addr != 0x200 {
+		t.Errorf("instruction 0 addr = %#x, want 0x200", instructions[0].Addr)
+	}
+	if instructions[1].Addr != 0x202 {
+		t.Errorf("instruction 1 addr = %#x, want 0x202", instructions[1].Addr)
+	}
+}
@@ -0,0 +1,95 @@
+package debug
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TextUI drives a Debugger from line-oriented commands read from r,
+// writing prompts and state dumps to w. Supported commands:
+//
+//	step                 execute one instruction
+//	back                 reverse-step one instruction
+//	continue [maxSteps]  run until a breakpoint trips (default 100000)
+//	break <addr-hex>     set a PC breakpoint
+//	info                 print the current State
+//	quit                 exit the loop
+type TextUI struct {
+	dbg *Debugger
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+// NewTextUI wires a TextUI to dbg, reading commands from r and writing
+// output to w.
+func NewTextUI(dbg *Debugger, r io.Reader, w io.Writer) *TextUI {
+	return &TextUI{dbg: dbg, in: bufio.NewScanner(r), out: w}
+}
+
+// Run processes commands from the UI's reader until "quit" or EOF.
+func (ui *TextUI) Run() {
+	for {
+		fmt.Fprint(ui.out, "(chip8dbg) ")
+		if !ui.in.Scan() {
+			return
+		}
+		if !ui.handle(strings.TrimSpace(ui.in.Text())) {
+			return
+		}
+	}
+}
+
+func (ui *TextUI) handle(line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return true
+	}
+
+	switch fields[0] {
+	case "step":
+		fired, err := ui.dbg.Step()
+		for _, wp := range fired {
+			fmt.Fprintf(ui.out, "watchpoint %q changed\n", wp.Name)
+		}
+		if err != nil {
+			fmt.Fprintf(ui.out, "trap: %v\n", err)
+		}
+		fmt.Fprintln(ui.out, ui.dbg.Dump())
+	case "back":
+		if !ui.dbg.ReverseStep() {
+			fmt.Fprintln(ui.out, "no history to rewind")
+		}
+		fmt.Fprintln(ui.out, ui.dbg.Dump())
+	case "continue":
+		max := 100000
+		if len(fields) > 1 {
+			if n, err := strconv.Atoi(fields[1]); err == nil {
+				max = n
+			}
+		}
+		steps := ui.dbg.RunUntil(max)
+		fmt.Fprintf(ui.out, "ran %d steps\n", steps)
+		fmt.Fprintln(ui.out, ui.dbg.Dump())
+	case "break":
+		if len(fields) < 2 {
+			fmt.Fprintln(ui.out, "usage: break <addr-hex>")
+			return true
+		}
+		addr, err := strconv.ParseUint(strings.TrimPrefix(fields[1], "0x"), 16, 16)
+		if err != nil {
+			fmt.Fprintf(ui.out, "bad address %q: %v\n", fields[1], err)
+			return true
+		}
+		ui.dbg.BreakAtPC(uint16(addr))
+	case "info":
+		fmt.Fprintln(ui.out, ui.dbg.Dump())
+	case "quit":
+		return false
+	default:
+		fmt.Fprintf(ui.out, "unknown command %q\n", fields[0])
+	}
+	return true
+}
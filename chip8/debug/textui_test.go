@@ -0,0 +1,93 @@
+package debug
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func newTextUI(t *testing.T, rom []byte) (*TextUI, *bytes.Buffer) {
+	t.Helper()
+	dbg := newDebugger(t, rom)
+	var out bytes.Buffer
+	return NewTextUI(dbg, strings.NewReader(""), &out), &out
+}
+
+func TestTextUIStepAndInfo(t *testing.T) {
+	ui, out := newTextUI(t, loopROM)
+
+	if !ui.handle("step") {
+		t.Fatalf("handle(step) returned false")
+	}
+	if ui.dbg.core.PC() != 0x202 {
+		t.Fatalf("PC = %#x after step, want 0x202", ui.dbg.core.PC())
+	}
+
+	out.Reset()
+	if !ui.handle("info") {
+		t.Fatalf("handle(info) returned false")
+	}
+	if !strings.Contains(out.String(), "PC=0202") {
+		t.Fatalf("info output %q missing PC=0202", out.String())
+	}
+}
+
+func TestTextUIBack(t *testing.T) {
+	ui, _ := newTextUI(t, loopROM)
+
+	ui.handle("step")
+	if ui.dbg.core.PC() != 0x202 {
+		t.Fatalf("PC = %#x after step, want 0x202", ui.dbg.core.PC())
+	}
+
+	ui.handle("back")
+	if ui.dbg.core.PC() != 0x200 {
+		t.Fatalf("PC = %#x after back, want 0x200", ui.dbg.core.PC())
+	}
+}
+
+func TestTextUIBreakAndContinue(t *testing.T) {
+	ui, out := newTextUI(t, loopROM)
+
+	ui.handle("break 0x204")
+	out.Reset()
+	if !ui.handle("continue") {
+		t.Fatalf("handle(continue) returned false")
+	}
+	if !strings.Contains(out.String(), "ran 2 steps") {
+		t.Fatalf("continue output %q, want \"ran 2 steps\"", out.String())
+	}
+	if ui.dbg.core.PC() != 0x204 {
+		t.Fatalf("PC = %#x after continue, want 0x204", ui.dbg.core.PC())
+	}
+}
+
+func TestTextUIBreakBadAddress(t *testing.T) {
+	ui, out := newTextUI(t, loopROM)
+
+	if !ui.handle("break nope") {
+		t.Fatalf("handle(break nope) returned false")
+	}
+	if !strings.Contains(out.String(), "bad address") {
+		t.Fatalf("output %q missing \"bad address\"", out.String())
+	}
+}
+
+func TestTextUIUnknownCommand(t *testing.T) {
+	ui, out := newTextUI(t, loopROM)
+
+	if !ui.handle("frobnicate") {
+		t.Fatalf("handle(frobnicate) returned false")
+	}
+	if !strings.Contains(out.String(), `unknown command "frobnicate"`) {
+		t.Fatalf("output %q missing unknown command message", out.String())
+	}
+}
+
+func TestTextUIQuit(t *testing.T) {
+	ui, _ := newTextUI(t, loopROM)
+
+	if ui.handle("quit") {
+		t.Fatalf("handle(quit) returned true, want false")
+	}
+}
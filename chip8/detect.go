@@ -0,0 +1,85 @@
+package chip8
+
+// detectScanInstructions bounds how much of the ROM DetectProfile treats
+// as an instruction stream. Code is front-loaded by every CHIP-8
+// toolchain, with sprite and data bytes placed after it, so scanning
+// only the front of the ROM keeps data that happens to look like a
+// dialect opcode further in from ever being considered.
+const detectScanInstructions = 256
+
+// detectMinMatches is the number of opcodes DetectProfile must see
+// before it trusts a dialect over the single byte pair coincidentally
+// matching one inside sprite data.
+const detectMinMatches = 2
+
+// DetectProfile inspects a bounded prefix of rom's opcodes and guesses
+// which dialect it targets, by counting instructions that only exist in
+// SCHIP or XO-CHIP. Classic CHIP-8 ROMs never emit these, so seeing
+// several is a reliable (if not exhaustive) signal; a ROM with fewer
+// than detectMinMatches falls back to ProfileChip8 rather than flip
+// profiles on a single coincidental match.
+func DetectProfile(rom []byte) Profile {
+	scanLen := detectScanInstructions * 2
+	if scanLen > len(rom) {
+		scanLen = len(rom)
+	}
+
+	var xoMatches, sChipMatches int
+	for i := 0; i+1 < scanLen; i += 2 {
+		opcode := uint16(rom[i])<<8 | uint16(rom[i+1])
+
+		if isXOChipOpcode(opcode) {
+			xoMatches++
+		}
+		if isSChipOpcode(opcode) {
+			sChipMatches++
+		}
+	}
+
+	if xoMatches >= detectMinMatches {
+		return ProfileXOChip
+	}
+	if sChipMatches >= detectMinMatches {
+		return ProfileSChip
+	}
+	return ProfileChip8
+}
+
+// isXOChipOpcode reports whether opcode only exists in the XO-CHIP
+// instruction set: 5xy2/5xy3 register range save/load, 00Dn scroll up,
+// Fx01/Fx02/Fx3A plane/audio-pattern/pitch, and F000 NNNN long load.
+func isXOChipOpcode(opcode uint16) bool {
+	switch opcode & 0xF00F {
+	case 0x5002, 0x5003:
+		return true
+	}
+	if opcode&0xFFF0 == 0x00D0 {
+		return true
+	}
+	switch opcode & 0xF0FF {
+	case 0xF001, 0xF002, 0xF03A:
+		return true
+	}
+	return opcode == 0xF000
+}
+
+// isSChipOpcode reports whether opcode only exists in the SCHIP
+// instruction set: the 00Cn/00FB/00FC/00FD/00FE/00FF display opcodes,
+// Dxy0 16x16 sprites, and Fx30/Fx75/Fx85 large font/RPL opcodes.
+func isSChipOpcode(opcode uint16) bool {
+	switch opcode {
+	case 0x00FB, 0x00FC, 0x00FD, 0x00FE, 0x00FF:
+		return true
+	}
+	if opcode&0xFFF0 == 0x00C0 {
+		return true
+	}
+	if opcode&0xF00F == 0xD000 {
+		return true
+	}
+	switch opcode & 0xF0FF {
+	case 0xF030, 0xF075, 0xF085:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package chip8
+
+import "testing"
+
+// TestDetectProfileIgnoresStrayDataBytes guards against a single
+// coincidental opcode-shaped byte pair in sprite/data bytes flipping the
+// whole profile, e.g. a 0x00, 0xD5 pair that happens to land where
+// DetectProfile is scanning but was never meant as an instruction.
+func TestDetectProfileIgnoresStrayDataBytes(t *testing.T) {
+	rom := make([]byte, 64)
+	rom[40], rom[41] = 0x00, 0xD5 // looks like XO-CHIP 00D5 (scroll up 5)
+
+	if got := DetectProfile(rom); got != ProfileChip8 {
+		t.Fatalf("DetectProfile = %v, want ProfileChip8", got)
+	}
+}
+
+// TestDetectProfileRequiresCorroboration checks that even within the
+// scanned prefix, a lone dialect-only opcode isn't enough to switch
+// profiles - DetectProfile should wait for a second corroborating match.
+func TestDetectProfileRequiresCorroboration(t *testing.T) {
+	rom := []byte{0x00, 0xFF} // a single SCHIP 00FF (switch to hi-res)
+
+	if got := DetectProfile(rom); got != ProfileChip8 {
+		t.Fatalf("DetectProfile = %v, want ProfileChip8", got)
+	}
+}
+
+func TestDetectProfileXOChip(t *testing.T) {
+	rom := []byte{
+		0xF0, 0x01, // FX01: select plane
+		0xF0, 0x02, // FX02: load audio pattern
+	}
+
+	if got := DetectProfile(rom); got != ProfileXOChip {
+		t.Fatalf("DetectProfile = %v, want ProfileXOChip", got)
+	}
+}
+
+func TestDetectProfileSChip(t *testing.T) {
+	rom := []byte{
+		0x00, 0xFF, // 00FF: switch to hi-res
+		0xF0, 0x75, // FX75: save RPL flags
+	}
+
+	if got := DetectProfile(rom); got != ProfileSChip {
+		t.Fatalf("DetectProfile = %v, want ProfileSChip", got)
+	}
+}
@@ -0,0 +1,206 @@
+package chip8
+
+// bigFontSet is the SCHIP 10-byte-per-glyph hex digit font (0-9, A-F),
+// used by Fx30 and loaded into memory right after the regular fontSet.
+var bigFontSet = [160]byte{
+	0x3C, 0x7E, 0xE7, 0xC3, 0xC3, 0xC3, 0xC3, 0xE7, 0x7E, 0x3C, // 0
+	0x18, 0x38, 0x58, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x3C, // 1
+	0x3E, 0x7F, 0xC3, 0x06, 0x0C, 0x18, 0x30, 0x60, 0xFF, 0xFF, // 2
+	0x3C, 0x7E, 0xC3, 0x03, 0x0E, 0x0E, 0x03, 0xC3, 0x7E, 0x3C, // 3
+	0x06, 0x0E, 0x1E, 0x36, 0x66, 0xC6, 0xFF, 0xFF, 0x06, 0x06, // 4
+	0xFF, 0xFF, 0xC0, 0xC0, 0xFC, 0xFE, 0x03, 0xC3, 0x7E, 0x3C, // 5
+	0x3E, 0x7C, 0xC0, 0xC0, 0xFC, 0xFE, 0xC3, 0xC3, 0x7E, 0x3C, // 6
+	0xFF, 0xFF, 0x03, 0x06, 0x0C, 0x18, 0x30, 0x60, 0x60, 0x60, // 7
+	0x3C, 0x7E, 0xC3, 0xC3, 0x7E, 0x7E, 0xC3, 0xC3, 0x7E, 0x3C, // 8
+	0x3C, 0x7E, 0xC3, 0xC3, 0x7F, 0x3F, 0x03, 0x03, 0x3E, 0x7C, // 9
+	0x18, 0x3C, 0x66, 0xC3, 0xC3, 0xFF, 0xFF, 0xC3, 0xC3, 0xC3, // A
+	0xFC, 0xFE, 0xC3, 0xC3, 0xFC, 0xFE, 0xC3, 0xC3, 0xFE, 0xFC, // B
+	0x3C, 0x7E, 0xC3, 0xC0, 0xC0, 0xC0, 0xC0, 0xC3, 0x7E, 0x3C, // C
+	0xFC, 0xFE, 0xC3, 0xC3, 0xC3, 0xC3, 0xC3, 0xC3, 0xFE, 0xFC, // D
+	0xFF, 0xFF, 0xC0, 0xC0, 0xFC, 0xFC, 0xC0, 0xC0, 0xFF, 0xFF, // E
+	0xFF, 0xFF, 0xC0, 0xC0, 0xFC, 0xFC, 0xC0, 0xC0, 0xC0, 0xC0, // F
+}
+
+// bigFontBase is the memory offset bigFontSet is loaded at, directly
+// after the 80-byte regular fontSet.
+const bigFontBase = 80
+
+func (c8 *Chip8) loadBigFontset() {
+	for i, b := range bigFontSet {
+		c8.memory[bigFontBase+i] = b
+	}
+}
+
+// drawSprite draws an 8xN (or, when n == 0, 16x16) sprite at (vx, vy)
+// to every plane selected by c8.plane, reading consecutive sprite rows
+// for each active plane in turn starting at I.
+func (c8 *Chip8) drawSprite(vx, vy, n byte) error {
+	mask := c8.plane
+	if mask == 0 {
+		mask = 1
+	}
+
+	x0 := int(vx) % c8.fb.width
+	y0 := int(vy) % c8.fb.height
+
+	wide := n == 0
+	rows := int(n)
+	cols := 8
+	if wide {
+		rows = 16
+		cols = 16
+	}
+
+	bytesPerRow := 1
+	if wide {
+		bytesPerRow = 2
+	}
+	planeCount := 0
+	for p := 0; p < 2; p++ {
+		if mask&(1<<uint(p)) != 0 {
+			planeCount++
+		}
+	}
+	if int(c8.i)+planeCount*rows*bytesPerRow > memSize {
+		return c8.trap(TrapInvalidSprite, "sprite data runs past end of memory")
+	}
+
+	c8.v[0xF] = 0
+
+	offset := 0
+	for p := 0; p < 2; p++ {
+		if mask&(1<<uint(p)) == 0 {
+			continue
+		}
+		// Each plane reads its own rows*bytesPerRow block, starting
+		// after every lower selected plane's block, regardless of how
+		// many of this plane's rows get clipped off-screen below -
+		// otherwise a vertically-clipped draw leaves the next plane
+		// reading from wherever this one's loop broke out early.
+		addr := c8.i + uint16(offset)
+		offset += rows * bytesPerRow
+		plane := c8.fb.planes[p]
+		for row := 0; row < rows; row++ {
+			var rowBits uint16
+			if wide {
+				rowBits = uint16(c8.memory[addr])<<8 | uint16(c8.memory[addr+1])
+				addr += 2
+			} else {
+				rowBits = uint16(c8.memory[addr]) << 8
+				addr++
+			}
+
+			y := y0 + row
+			if y >= c8.fb.height {
+				break
+			}
+			for col := 0; col < cols; col++ {
+				if rowBits&(0x8000>>uint(col)) == 0 {
+					continue
+				}
+				x := x0 + col
+				if x >= c8.fb.width {
+					break
+				}
+				idx := y*c8.fb.width + x
+				if plane[idx] != 0 {
+					c8.v[0xF] = 1
+				}
+				plane[idx] ^= 1
+			}
+		}
+	}
+
+	c8.drawFlag = true
+	return nil
+}
+
+// exit00FD implements 00FD (XO-CHIP/SCHIP "exit the interpreter") by
+// setting exited, which Run checks.
+func (c8 *Chip8) exit00FD() {
+	c8.exited = true
+}
+
+// scroll00Cn implements 00Cn: scroll the display down n pixels.
+func (c8 *Chip8) scroll00Cn(n byte) {
+	c8.fb.scrollDown(int(n), c8.planeOrDefault())
+}
+
+// scroll00Dn implements the XO-CHIP 00Dn: scroll the display up n pixels.
+func (c8 *Chip8) scroll00Dn(n byte) {
+	c8.fb.scrollUp(int(n), c8.planeOrDefault())
+}
+
+func (c8 *Chip8) scroll00FB() {
+	c8.fb.scrollRight(4, c8.planeOrDefault())
+}
+
+func (c8 *Chip8) scroll00FC() {
+	c8.fb.scrollLeft(4, c8.planeOrDefault())
+}
+
+func (c8 *Chip8) planeOrDefault() byte {
+	if c8.plane == 0 {
+		return 1
+	}
+	return c8.plane
+}
+
+// saveRange implements XO-CHIP 5xy2: save VX..VY (inclusive, in either
+// direction) to memory starting at I, without moving I.
+func (c8 *Chip8) saveRange(x, y byte) error {
+	lo, hi := x, y
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	for idx := lo; idx <= hi; idx++ {
+		if err := c8.writeMem(c8.i+uint16(idx-lo), c8.v[idx]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadRange implements XO-CHIP 5xy3: load VX..VY (inclusive, in either
+// direction) from memory starting at I, without moving I.
+func (c8 *Chip8) loadRange(x, y byte) error {
+	lo, hi := x, y
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	for idx := lo; idx <= hi; idx++ {
+		v, err := c8.readMem(c8.i + uint16(idx-lo))
+		if err != nil {
+			return err
+		}
+		c8.v[idx] = v
+	}
+	return nil
+}
+
+// saveRPL implements Fx75: persist V0..VX to the RPL flag registers.
+func (c8 *Chip8) saveRPL(x byte) {
+	for idx := byte(0); idx <= x && idx < byte(len(c8.rpl)); idx++ {
+		c8.rpl[idx] = c8.v[idx]
+	}
+}
+
+// loadRPL implements Fx85: restore V0..VX from the RPL flag registers.
+func (c8 *Chip8) loadRPL(x byte) {
+	for idx := byte(0); idx <= x && idx < byte(len(c8.rpl)); idx++ {
+		c8.v[idx] = c8.rpl[idx]
+	}
+}
+
+// loadAudioPattern implements XO-CHIP Fx02: load the 16-byte 1-bit
+// audio playback pattern from memory starting at I.
+func (c8 *Chip8) loadAudioPattern() error {
+	for i := 0; i < len(c8.audioPattern); i++ {
+		v, err := c8.readMem(c8.i + uint16(i))
+		if err != nil {
+			return err
+		}
+		c8.audioPattern[i] = v
+	}
+	return nil
+}
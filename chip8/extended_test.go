@@ -0,0 +1,172 @@
+package chip8
+
+import "testing"
+
+// resizeTrackingDisplay records the last size passed to Resize, so tests
+// can check the hi-res toggle actually notifies the Display instead of
+// just poking the framebuffer.
+type resizeTrackingDisplay struct {
+	stubDisplay
+	width, height int
+	resizes       int
+}
+
+func (d *resizeTrackingDisplay) Resize(width, height int) {
+	d.width, d.height = width, height
+	d.resizes++
+}
+
+func newExtendedChip8(t *testing.T, rom []byte, profile Profile, display Display) *Chip8 {
+	t.Helper()
+	c8, err := LoadFromBytes(rom, display, stubKeyboard{}, stubBuzzer{}, nil, WithQuirks(profile))
+	if err != nil {
+		t.Fatalf("LoadFromBytes: %v", err)
+	}
+	return c8
+}
+
+func step(t *testing.T, c8 *Chip8) {
+	t.Helper()
+	if err := c8.EmulateCycle(); err != nil {
+		t.Fatalf("EmulateCycle: %v", err)
+	}
+}
+
+func TestScrollRight00FB(t *testing.T) {
+	c8 := newExtendedChip8(t, []byte{0x00, 0xFB}, ProfileSChip, stubDisplay{})
+	c8.fb.planes[0][0] = 1 // pixel at (0, 0)
+
+	step(t, c8)
+
+	if c8.fb.planes[0][0] != 0 {
+		t.Fatalf("pixel at (0,0) still set after scroll right")
+	}
+	if c8.fb.planes[0][4] != 1 {
+		t.Fatalf("pixel did not land at (4,0) after scroll right 4")
+	}
+}
+
+func TestScrollLeft00FC(t *testing.T) {
+	c8 := newExtendedChip8(t, []byte{0x00, 0xFC}, ProfileSChip, stubDisplay{})
+	c8.fb.planes[0][4] = 1 // pixel at (4, 0)
+
+	step(t, c8)
+
+	if c8.fb.planes[0][4] != 0 {
+		t.Fatalf("pixel at (4,0) still set after scroll left")
+	}
+	if c8.fb.planes[0][0] != 1 {
+		t.Fatalf("pixel did not land at (0,0) after scroll left 4")
+	}
+}
+
+func TestScrollDown00Cn(t *testing.T) {
+	c8 := newExtendedChip8(t, []byte{0x00, 0xC2}, ProfileSChip, stubDisplay{}) // scroll down 2
+	c8.fb.planes[0][0] = 1                                                     // pixel at (0, 0)
+
+	step(t, c8)
+
+	if c8.fb.planes[0][0] != 0 {
+		t.Fatalf("pixel at (0,0) still set after scroll down")
+	}
+	if c8.fb.planes[0][2*c8.fb.width] != 1 {
+		t.Fatalf("pixel did not land at (0,2) after scroll down 2")
+	}
+}
+
+func TestScrollUp00Dn(t *testing.T) {
+	c8 := newExtendedChip8(t, []byte{0x00, 0xD2}, ProfileXOChip, stubDisplay{}) // scroll up 2
+	c8.fb.planes[0][2*c8.fb.width] = 1                                          // pixel at (0, 2)
+
+	step(t, c8)
+
+	if c8.fb.planes[0][2*c8.fb.width] != 0 {
+		t.Fatalf("pixel at (0,2) still set after scroll up")
+	}
+	if c8.fb.planes[0][0] != 1 {
+		t.Fatalf("pixel did not land at (0,0) after scroll up 2")
+	}
+}
+
+func TestHiResToggle(t *testing.T) {
+	rom := []byte{
+		0x00, 0xFF, // 0x200: switch to hi-res (128x64)
+		0x00, 0xFE, // 0x202: switch back to low-res (64x32)
+	}
+	display := &resizeTrackingDisplay{}
+	c8 := newExtendedChip8(t, rom, ProfileSChip, display)
+
+	step(t, c8)
+	if c8.fb.width != 128 || c8.fb.height != 64 {
+		t.Fatalf("framebuffer = %dx%d, want 128x64", c8.fb.width, c8.fb.height)
+	}
+	if display.resizes != 1 || display.width != 128 || display.height != 64 {
+		t.Fatalf("Display.Resize not called with 128x64: %+v", display)
+	}
+
+	step(t, c8)
+	if c8.fb.width != 64 || c8.fb.height != 32 {
+		t.Fatalf("framebuffer = %dx%d, want 64x32", c8.fb.width, c8.fb.height)
+	}
+	if display.resizes != 2 || display.width != 64 || display.height != 32 {
+		t.Fatalf("Display.Resize not called with 64x32: %+v", display)
+	}
+}
+
+func TestDrawSprite16x16(t *testing.T) {
+	rom := []byte{
+		0x60, 0x00, // 0x200: LD V0, 0
+		0x61, 0x00, // 0x202: LD V1, 0
+		0xA2, 0x0A, // 0x204: LD I, 0x20A
+		0xD0, 0x10, // 0x206: DRW V0, V1, 0 (16x16 sprite)
+	}
+	c8 := newExtendedChip8(t, rom, ProfileSChip, stubDisplay{})
+	for i := 0; i < 32; i++ {
+		c8.memory[0x20A+i] = 0xFF // every row fully on, both bytes
+	}
+
+	step(t, c8)
+	step(t, c8)
+	step(t, c8)
+	step(t, c8)
+
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			if c8.fb.planes[0][y*c8.fb.width+x] == 0 {
+				t.Fatalf("pixel (%d,%d) not set by 16x16 sprite draw", x, y)
+			}
+		}
+	}
+}
+
+// TestDrawSpriteDualPlaneClipping reproduces a vertically-clipped
+// dual-plane XO-CHIP draw: an 8x4 sprite at y=30 on a 32-tall screen
+// only has 2 of its 4 rows on-screen. Plane 1 must still read its rows
+// starting at c8.i+rows*bytesPerRow, not from wherever plane 0's
+// clipped loop happened to stop.
+func TestDrawSpriteDualPlaneClipping(t *testing.T) {
+	rom := []byte{
+		0x60, 0x00, // 0x200: LD V0, 0
+		0x61, 0x1E, // 0x202: LD V1, 30
+		0xA2, 0x0A, // 0x204: LD I, 0x20A
+		0xF3, 0x01, // 0x206: select planes 0 and 1
+		0xD0, 0x14, // 0x208: DRW V0, V1, 4 (8x4 sprite)
+	}
+	c8 := newExtendedChip8(t, rom, ProfileXOChip, stubDisplay{})
+	copy(c8.memory[0x20A:], []byte{0xAA, 0xBB, 0xCC, 0xDD, 0x11, 0x22, 0x33, 0x44})
+
+	for i := 0; i < 5; i++ {
+		step(t, c8)
+	}
+
+	// 0x11 = 0b00010001: columns 3 and 7 on.
+	row0 := 30 * c8.fb.width
+	if c8.fb.planes[1][row0+3] == 0 || c8.fb.planes[1][row0+7] == 0 {
+		t.Fatalf("plane 1 row 0 (y=30) wrong: want cols 3,7 set from 0x11")
+	}
+	// 0x22 = 0b00100010: columns 2 and 6 on.
+	row1 := 31 * c8.fb.width
+	if c8.fb.planes[1][row1+2] == 0 || c8.fb.planes[1][row1+6] == 0 {
+		t.Fatalf("plane 1 row 1 (y=31) wrong: want cols 2,6 set from 0x22")
+	}
+}
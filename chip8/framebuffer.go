@@ -0,0 +1,140 @@
+package chip8
+
+// framebuffer is a resizable, two-plane pixel buffer. Plane 0 is the
+// only plane used by classic CHIP-8 and SUPER-CHIP; XO-CHIP draws to
+// either or both planes, with the combination of the two giving up to
+// four colors.
+type framebuffer struct {
+	width, height int
+	planes        [2][]byte
+}
+
+func newFramebuffer() *framebuffer {
+	fb := &framebuffer{}
+	fb.resize(64, 32)
+	return fb
+}
+
+func (fb *framebuffer) resize(w, h int) {
+	fb.width, fb.height = w, h
+	fb.planes[0] = make([]byte, w*h)
+	fb.planes[1] = make([]byte, w*h)
+}
+
+func (fb *framebuffer) hiRes() bool {
+	return fb.width == 128
+}
+
+func (fb *framebuffer) setHiRes(hi bool) {
+	if hi == fb.hiRes() {
+		return
+	}
+	if hi {
+		fb.resize(128, 64)
+	} else {
+		fb.resize(64, 32)
+	}
+}
+
+// clear zeroes every plane selected by mask (bit 0 = plane 0, bit 1 =
+// plane 1).
+func (fb *framebuffer) clear(mask byte) {
+	for p := 0; p < 2; p++ {
+		if mask&(1<<uint(p)) == 0 {
+			continue
+		}
+		for i := range fb.planes[p] {
+			fb.planes[p][i] = 0
+		}
+	}
+}
+
+// colorAt returns the combined color index (0-3) at (x, y): bit 0 from
+// plane 0, bit 1 from plane 1.
+func (fb *framebuffer) colorAt(x, y int) byte {
+	idx := y*fb.width + x
+	var v byte
+	if fb.planes[0][idx] != 0 {
+		v |= 1
+	}
+	if fb.planes[1][idx] != 0 {
+		v |= 2
+	}
+	return v
+}
+
+func (fb *framebuffer) scrollDown(n int, mask byte) {
+	for p := 0; p < 2; p++ {
+		if mask&(1<<uint(p)) == 0 {
+			continue
+		}
+		plane := fb.planes[p]
+		for y := fb.height - 1; y >= 0; y-- {
+			for x := 0; x < fb.width; x++ {
+				src := y - n
+				if src < 0 {
+					plane[y*fb.width+x] = 0
+				} else {
+					plane[y*fb.width+x] = plane[src*fb.width+x]
+				}
+			}
+		}
+	}
+}
+
+func (fb *framebuffer) scrollUp(n int, mask byte) {
+	for p := 0; p < 2; p++ {
+		if mask&(1<<uint(p)) == 0 {
+			continue
+		}
+		plane := fb.planes[p]
+		for y := 0; y < fb.height; y++ {
+			for x := 0; x < fb.width; x++ {
+				src := y + n
+				if src >= fb.height {
+					plane[y*fb.width+x] = 0
+				} else {
+					plane[y*fb.width+x] = plane[src*fb.width+x]
+				}
+			}
+		}
+	}
+}
+
+func (fb *framebuffer) scrollRight(n int, mask byte) {
+	for p := 0; p < 2; p++ {
+		if mask&(1<<uint(p)) == 0 {
+			continue
+		}
+		plane := fb.planes[p]
+		for y := 0; y < fb.height; y++ {
+			for x := fb.width - 1; x >= 0; x-- {
+				src := x - n
+				if src < 0 {
+					plane[y*fb.width+x] = 0
+				} else {
+					plane[y*fb.width+x] = plane[y*fb.width+src]
+				}
+			}
+		}
+	}
+}
+
+func (fb *framebuffer) scrollLeft(n int, mask byte) {
+	for p := 0; p < 2; p++ {
+		if mask&(1<<uint(p)) == 0 {
+			continue
+		}
+		plane := fb.planes[p]
+		for y := 0; y < fb.height; y++ {
+			for x := 0; x < fb.width; x++ {
+				src := x + n
+				if src >= fb.width {
+					plane[y*fb.width+x] = 0
+				} else {
+					plane[y*fb.width+x] = plane[y*fb.width+src]
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package chip8
+
+import (
+	"context"
+	"math/rand"
+)
+
+// Display receives the framebuffer after a cycle that changed it (a
+// sprite draw, clear or scroll). SetPixel is called for every pixel of
+// the framebuffer; Refresh is called once afterwards so a frontend can
+// batch the actual present/flip.
+type Display interface {
+	// SetPixel sets the on/off state of the pixel at (x, y).
+	SetPixel(x, y int, on bool)
+	// Refresh is called after a frame's worth of SetPixel calls and
+	// should push the framebuffer to the screen.
+	Refresh() error
+	// Resize is called whenever the framebuffer resolution changes, e.g.
+	// the SCHIP 00FE/00FF low/hi-res toggle, so a frontend can resize
+	// its window or surface before the next SetPixel/Refresh.
+	Resize(width, height int)
+}
+
+// Keyboard reports the live state of the 16-key hex keypad (0x0-0xF).
+type Keyboard interface {
+	// IsDown reports whether the given hex key is currently pressed.
+	IsDown(key byte) bool
+	// WaitKey blocks until a key is pressed or ctx is done, returning the
+	// key in the former case and ctx.Err() in the latter. Used by the
+	// FX0A opcode, which halts the CPU until a key event arrives; the
+	// ctx lets Run's cycle loop keep observing cancellation while FX0A
+	// is waiting instead of blocking forever.
+	WaitKey(ctx context.Context) (byte, error)
+}
+
+// Buzzer is driven by the sound timer: Start is called the instant the
+// timer becomes non-zero, Stop the instant it reaches zero.
+type Buzzer interface {
+	Start()
+	Stop()
+}
+
+// RandSource supplies the random byte consumed by the CXNN opcode. It
+// exists so tests can inject a deterministic source.
+type RandSource interface {
+	Intn(n int) int
+}
+
+// mathRand is the default RandSource, backed by math/rand.
+type mathRand struct{}
+
+func (mathRand) Intn(n int) int {
+	return rand.Intn(n)
+}
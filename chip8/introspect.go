@@ -0,0 +1,44 @@
+package chip8
+
+// This file exposes read-only introspection into emulator state, plus
+// a single-step entry point, for tooling (chip8/debug) that lives
+// outside the package and so cannot see the unexported fields directly.
+
+// PC returns the current program counter.
+func (c8 *Chip8) PC() uint16 { return c8.pc }
+
+// I returns the current index register.
+func (c8 *Chip8) I() uint16 { return c8.i }
+
+// SP returns the current stack pointer.
+func (c8 *Chip8) SP() uint16 { return c8.sp }
+
+// Opcode returns the most recently fetched opcode.
+func (c8 *Chip8) Opcode() uint16 { return c8.opcode }
+
+// V returns the value of register Vx (0-15).
+func (c8 *Chip8) V(x int) byte { return c8.v[x] }
+
+// Stack returns a copy of the call stack.
+func (c8 *Chip8) Stack() [16]uint16 { return c8.stack }
+
+// DelayTimer returns the current delay timer value.
+func (c8 *Chip8) DelayTimer() byte { return c8.delayTimer }
+
+// SoundTimer returns the current sound timer value.
+func (c8 *Chip8) SoundTimer() byte { return c8.soundTimer }
+
+// MemoryAt reads a single byte of memory.
+func (c8 *Chip8) MemoryAt(addr uint16) byte { return c8.memory[addr] }
+
+// MemoryRange copies len(into) bytes of memory starting at addr into into.
+func (c8 *Chip8) MemoryRange(addr uint16, into []byte) {
+	copy(into, c8.memory[addr:])
+}
+
+// Step executes a single CPU cycle and returns any *TrapError it hits.
+// It is the entry point a Debugger uses to single-step, bypassing Run's
+// clock.
+func (c8 *Chip8) Step() error {
+	return c8.EmulateCycle()
+}
@@ -0,0 +1,151 @@
+package chip8
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+)
+
+const maxRomSize = 0xFFF - 0x200
+
+// ErrRomTooLarge is thrown if the read in ROM size is larger than the max
+var ErrRomTooLarge = errors.New("rom size is too large")
+
+// Option configures a Chip8 being constructed by LoadFromBytes and the
+// loaders built on top of it, before the first cycle runs.
+type Option func(*loadConfig)
+
+type loadConfig struct {
+	profile      Profile
+	profileSet   bool
+	clockHz      int
+	startAddress uint16
+}
+
+// WithQuirks pins the emulator to profile's quirk set instead of letting
+// it be guessed from the ROM by DetectProfile.
+func WithQuirks(profile Profile) Option {
+	return func(cfg *loadConfig) {
+		cfg.profile = profile
+		cfg.profileSet = true
+	}
+}
+
+// WithClockHz overrides the default CPU clock speed.
+func WithClockHz(hz int) Option {
+	return func(cfg *loadConfig) {
+		cfg.clockHz = hz
+	}
+}
+
+// WithStartAddress overrides the address ROM bytes are loaded at and
+// execution begins from. Most CHIP-8 ROMs expect 0x200; some ETI-660
+// ROMs expect 0x600.
+func WithStartAddress(addr uint16) Option {
+	return func(cfg *loadConfig) {
+		cfg.startAddress = addr
+	}
+}
+
+// LoadFromBytes constructs a Chip8 from ROM bytes already in memory,
+// wired up to the given peripherals. display, keyboard and buzzer may
+// not be nil; rand may be nil, in which case math/rand is used. With no
+// WithQuirks option, the dialect is guessed by DetectProfile.
+func LoadFromBytes(rom []byte, display Display, keyboard Keyboard, buzzer Buzzer, rand RandSource, opts ...Option) (*Chip8, error) {
+	if len(rom) > maxRomSize {
+		return nil, ErrRomTooLarge
+	}
+	if rand == nil {
+		rand = mathRand{}
+	}
+
+	cfg := loadConfig{clockHz: defaultClockHz, startAddress: 0x200}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if !cfg.profileSet {
+		cfg.profile = DetectProfile(rom)
+	}
+
+	c8 := &Chip8{
+		pc:          cfg.startAddress,
+		fb:          newFramebuffer(),
+		plane:       1,
+		vblankReady: true,
+		quirks:      DefaultQuirks(cfg.profile),
+		display:     display,
+		keyboard:    keyboard,
+		buzzer:      buzzer,
+		rand:        rand,
+		clockHz:     cfg.clockHz,
+	}
+	c8.loadFontset()
+	c8.loadBigFontset()
+
+	for i, b := range rom {
+		addr := int(cfg.startAddress) + i
+		if addr >= memSize {
+			return nil, ErrRomTooLarge
+		}
+		c8.memory[addr] = b
+	}
+
+	return c8, nil
+}
+
+// LoadFromReader reads a ROM to completion from r and constructs a
+// Chip8 from it; see LoadFromBytes for the peripheral and Option
+// parameters.
+func LoadFromReader(r io.Reader, display Display, keyboard Keyboard, buzzer Buzzer, rand RandSource, opts ...Option) (*Chip8, error) {
+	rom, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return LoadFromBytes(rom, display, keyboard, buzzer, rand, opts...)
+}
+
+// LoadROMFromFile reads a ROM from filepath; see LoadFromBytes for the
+// peripheral and Option parameters. This is the replacement for the
+// old filepath-only New constructor.
+func LoadROMFromFile(filepath string, display Display, keyboard Keyboard, buzzer Buzzer, rand RandSource, opts ...Option) (*Chip8, error) {
+	rom, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	return LoadFromBytes(rom, display, keyboard, buzzer, rand, opts...)
+}
+
+// LoadFromFS reads a ROM named name out of fsys - typically an
+// embed.FS of bundled public-domain ROMs - and constructs a Chip8 from
+// it; see LoadFromBytes for the peripheral and Option parameters.
+func LoadFromFS(fsys fs.FS, name string, display Display, keyboard Keyboard, buzzer Buzzer, rand RandSource, opts ...Option) (*Chip8, error) {
+	rom, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return LoadFromBytes(rom, display, keyboard, buzzer, rand, opts...)
+}
+
+// LoadFromURL fetches a ROM over HTTP(S), e.g. from a CHIP-8 archive
+// site, and constructs a Chip8 from it; see LoadFromBytes for the
+// peripheral and Option parameters.
+func LoadFromURL(url string, display Display, keyboard Keyboard, buzzer Buzzer, rand RandSource, opts ...Option) (*Chip8, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("chip8: fetch %s: unexpected status %s", url, resp.Status)
+	}
+
+	rom, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return LoadFromBytes(rom, display, keyboard, buzzer, rand, opts...)
+}
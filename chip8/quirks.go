@@ -0,0 +1,62 @@
+package chip8
+
+// Profile selects which CHIP-8 dialect's default Quirks a Chip8 should
+// boot with.
+type Profile int
+
+const (
+	// ProfileChip8 is the original 1977 COSMAC VIP interpreter behavior.
+	ProfileChip8 Profile = iota
+	// ProfileSChip is SUPER-CHIP 1.1, adding 128x64 hi-res mode.
+	ProfileSChip
+	// ProfileXOChip is the XO-CHIP dialect, adding a second bitplane,
+	// a bigger address space and an audio pattern buffer.
+	ProfileXOChip
+)
+
+// Quirks controls behavior that differs between CHIP-8 interpreters.
+// Running a ROM against the wrong Quirks is the single most common
+// cause of a "correct" emulator producing garbled output.
+type Quirks struct {
+	// ShiftUsesVX makes 8XY6/8XYE shift VX in place. When false (the
+	// original COSMAC VIP behavior), VY is shifted into VX first.
+	ShiftUsesVX bool
+	// LoadStoreLeavesI makes FX55/FX65 leave I unchanged. When false
+	// (the original behavior), I is left at I+X+1 after the loop.
+	LoadStoreLeavesI bool
+	// JumpOffsetVX makes BXNN jump to XNN plus the register named by the
+	// top nibble of X, rather than always NNN plus V0.
+	JumpOffsetVX bool
+	// DisplayWait makes DXYN block until the next 60Hz timer tick,
+	// matching the original interpreter's reliance on vblank to pace
+	// drawing. SCHIP and XO-CHIP ROMs generally expect this disabled.
+	DisplayWait bool
+}
+
+// DefaultQuirks returns the conventional Quirks configuration for the
+// given dialect.
+func DefaultQuirks(p Profile) Quirks {
+	switch p {
+	case ProfileSChip:
+		return Quirks{
+			ShiftUsesVX:      true,
+			LoadStoreLeavesI: true,
+			JumpOffsetVX:     true,
+			DisplayWait:      false,
+		}
+	case ProfileXOChip:
+		return Quirks{
+			ShiftUsesVX:      true,
+			LoadStoreLeavesI: true,
+			JumpOffsetVX:     true,
+			DisplayWait:      false,
+		}
+	default:
+		return Quirks{
+			ShiftUsesVX:      false,
+			LoadStoreLeavesI: false,
+			JumpOffsetVX:     false,
+			DisplayWait:      true,
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package sdl
+
+import (
+	"math"
+	"unsafe"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// buzzerFreqHz and sampleRate pick a simple, recognizable square-wave
+// tone matching the original CHIP-8 beeper.
+const (
+	buzzerFreqHz = 440
+	sampleRate   = 44100
+)
+
+// openBuzzerDevice opens an SDL audio device that continuously renders
+// a square wave via callback; the device starts paused, and Frontend's
+// Start/Stop toggle playback to mirror the sound timer.
+func openBuzzerDevice() (sdl.AudioDeviceID, error) {
+	phase := 0.0
+	phaseStep := buzzerFreqHz / float64(sampleRate)
+
+	spec := &sdl.AudioSpec{
+		Freq:     sampleRate,
+		Format:   sdl.AUDIO_S16SYS,
+		Channels: 1,
+		Samples:  1024,
+		Callback: sdl.AudioCallback(func(userdata unsafe.Pointer, stream *uint8, length int32) {
+			buf := unsafe.Slice((*int16)(unsafe.Pointer(stream)), int(length)/2)
+			for i := range buf {
+				if phase < 0.5 {
+					buf[i] = math.MaxInt16 / 4
+				} else {
+					buf[i] = math.MinInt16 / 4
+				}
+				phase += phaseStep
+				if phase >= 1 {
+					phase -= 1
+				}
+			}
+		}),
+	}
+
+	dev, err := sdl.OpenAudioDevice("", false, spec, nil, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	sdl.PauseAudioDevice(dev, true)
+	return dev, nil
+}
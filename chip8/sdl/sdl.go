@@ -0,0 +1,202 @@
+// Package sdl provides a chip8.Display, chip8.Keyboard and chip8.Buzzer
+// implemented on top of github.com/veandco/go-sdl2/sdl.
+package sdl
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// defaultScale is the number of screen pixels per CHIP-8 pixel.
+const defaultScale = 10
+
+// chip8Width and chip8Height are the classic CHIP-8 framebuffer
+// dimensions New sizes the window for; Resize grows the window if the
+// core switches into SCHIP/XO-CHIP's 128x64 hi-res mode.
+const (
+	chip8Width  = 64
+	chip8Height = 32
+)
+
+// keymap maps the standard CHIP-8 hex keypad onto the 4x4 block of keys
+// most keyboards have in the same layout:
+//
+//	1 2 3 C        1 2 3 4
+//	4 5 6 D   ->   Q W E R
+//	7 8 9 E        A S D F
+//	A 0 B F        Z X C V
+var keymap = map[sdl.Keycode]byte{
+	sdl.K_1: 0x1, sdl.K_2: 0x2, sdl.K_3: 0x3, sdl.K_4: 0xC,
+	sdl.K_q: 0x4, sdl.K_w: 0x5, sdl.K_e: 0x6, sdl.K_r: 0xD,
+	sdl.K_a: 0x7, sdl.K_s: 0x8, sdl.K_d: 0x9, sdl.K_f: 0xE,
+	sdl.K_z: 0xA, sdl.K_x: 0x0, sdl.K_c: 0xB, sdl.K_v: 0xF,
+}
+
+// Frontend bundles a Display, Keyboard and Buzzer backed by a single SDL
+// window, audio device and event pump.
+type Frontend struct {
+	window   *sdl.Window
+	renderer *sdl.Renderer
+	audioDev sdl.AudioDeviceID
+
+	scale int
+
+	// keysMu guards keys, which PumpEvents writes from the main thread
+	// while IsDown and WaitKey read (and WaitKey writes) it from
+	// whatever goroutine is driving Chip8.Run.
+	keysMu sync.Mutex
+	keys   [16]bool
+}
+
+// New opens an SDL window sized for the CHIP-8 framebuffer at the given
+// scale (pixels per CHIP-8 pixel) and an audio device for the buzzer.
+func New(title string, scale int) (*Frontend, error) {
+	if scale <= 0 {
+		scale = defaultScale
+	}
+
+	if err := sdl.Init(sdl.INIT_VIDEO | sdl.INIT_AUDIO); err != nil {
+		return nil, fmt.Errorf("sdl: init: %w", err)
+	}
+
+	window, err := sdl.CreateWindow(
+		title,
+		sdl.WINDOWPOS_CENTERED, sdl.WINDOWPOS_CENTERED,
+		int32(chip8Width*scale), int32(chip8Height*scale),
+		sdl.WINDOW_SHOWN,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("sdl: create window: %w", err)
+	}
+
+	renderer, err := sdl.CreateRenderer(window, -1, sdl.RENDERER_ACCELERATED)
+	if err != nil {
+		window.Destroy()
+		return nil, fmt.Errorf("sdl: create renderer: %w", err)
+	}
+
+	f := &Frontend{window: window, renderer: renderer, scale: scale}
+
+	dev, err := openBuzzerDevice()
+	if err != nil {
+		renderer.Destroy()
+		window.Destroy()
+		return nil, err
+	}
+	f.audioDev = dev
+
+	return f, nil
+}
+
+// Close tears down the window, renderer and audio device.
+func (f *Frontend) Close() {
+	sdl.PauseAudioDevice(f.audioDev, true)
+	sdl.CloseAudioDevice(f.audioDev)
+	f.renderer.Destroy()
+	f.window.Destroy()
+}
+
+// PumpEvents drains the SDL event queue and updates key state. It must
+// be called regularly (e.g. once per CPU cycle) from the main thread,
+// since SDL requires event polling to happen there.
+func (f *Frontend) PumpEvents() {
+	for {
+		event := sdl.PollEvent()
+		if event == nil {
+			return
+		}
+
+		switch e := event.(type) {
+		case *sdl.KeyboardEvent:
+			key, ok := keymap[e.Keysym.Sym]
+			if !ok {
+				continue
+			}
+			f.keysMu.Lock()
+			f.keys[key] = e.State == sdl.PRESSED
+			f.keysMu.Unlock()
+		}
+	}
+}
+
+// SetPixel implements chip8.Display.
+func (f *Frontend) SetPixel(x, y int, on bool) {
+	if on {
+		f.renderer.SetDrawColor(255, 255, 255, 255)
+	} else {
+		f.renderer.SetDrawColor(0, 0, 0, 255)
+	}
+	rect := sdl.Rect{
+		X: int32(x * f.scale),
+		Y: int32(y * f.scale),
+		W: int32(f.scale),
+		H: int32(f.scale),
+	}
+	f.renderer.FillRect(&rect)
+}
+
+// Refresh implements chip8.Display.
+func (f *Frontend) Refresh() error {
+	f.renderer.Present()
+	return nil
+}
+
+// Resize implements chip8.Display by growing or shrinking the window to
+// match the new framebuffer dimensions at the configured scale, so
+// switching into SCHIP/XO-CHIP hi-res mode (128x64) doesn't draw past a
+// window still sized for the classic 64x32 framebuffer.
+func (f *Frontend) Resize(width, height int) {
+	f.window.SetSize(int32(width*f.scale), int32(height*f.scale))
+}
+
+// IsDown implements chip8.Keyboard.
+func (f *Frontend) IsDown(key byte) bool {
+	if key > 0xF {
+		return false
+	}
+	f.keysMu.Lock()
+	defer f.keysMu.Unlock()
+	return f.keys[key]
+}
+
+// waitEventPollMs bounds how long WaitKey blocks on the SDL event queue
+// between checks of ctx, so cancelling ctx doesn't have to wait for a
+// key event that may never come.
+const waitEventPollMs = 50
+
+// WaitKey implements chip8.Keyboard by polling the SDL event queue until
+// a key-down event maps to a hex key or ctx is done.
+func (f *Frontend) WaitKey(ctx context.Context) (byte, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		default:
+		}
+
+		event := sdl.WaitEventTimeout(waitEventPollMs)
+		e, ok := event.(*sdl.KeyboardEvent)
+		if !ok || e.State != sdl.PRESSED {
+			continue
+		}
+		if key, ok := keymap[e.Keysym.Sym]; ok {
+			f.keysMu.Lock()
+			f.keys[key] = true
+			f.keysMu.Unlock()
+			return key, nil
+		}
+	}
+}
+
+// Start implements chip8.Buzzer.
+func (f *Frontend) Start() {
+	sdl.PauseAudioDevice(f.audioDev, false)
+}
+
+// Stop implements chip8.Buzzer.
+func (f *Frontend) Stop() {
+	sdl.PauseAudioDevice(f.audioDev, true)
+}
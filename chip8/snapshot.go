@@ -0,0 +1,207 @@
+package chip8
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// snapshotMagic identifies a Chip8 snapshot blob. snapshotVersion is
+// bumped whenever the layout changes, so Load can reject data written
+// by an incompatible version instead of silently misreading it.
+var snapshotMagic = [4]byte{'C', 'H', '8', 'S'}
+
+const snapshotVersion uint16 = 1
+
+// ErrBadSnapshot is returned by Load when data is not a recognized
+// Chip8 snapshot.
+var ErrBadSnapshot = errors.New("chip8: not a valid snapshot")
+
+// ErrSnapshotVersion is returned by Load when data was written by an
+// unsupported snapshot format version.
+var ErrSnapshotVersion = errors.New("chip8: unsupported snapshot version")
+
+// Bits of the packed quirks byte written by Snapshot and read by Load.
+const (
+	quirkShiftUsesVX = 1 << iota
+	quirkLoadStoreLeavesI
+	quirkJumpOffsetVX
+	quirkDisplayWait
+)
+
+func packQuirks(q Quirks) byte {
+	var b byte
+	if q.ShiftUsesVX {
+		b |= quirkShiftUsesVX
+	}
+	if q.LoadStoreLeavesI {
+		b |= quirkLoadStoreLeavesI
+	}
+	if q.JumpOffsetVX {
+		b |= quirkJumpOffsetVX
+	}
+	if q.DisplayWait {
+		b |= quirkDisplayWait
+	}
+	return b
+}
+
+func unpackQuirks(b byte) Quirks {
+	return Quirks{
+		ShiftUsesVX:      b&quirkShiftUsesVX != 0,
+		LoadStoreLeavesI: b&quirkLoadStoreLeavesI != 0,
+		JumpOffsetVX:     b&quirkJumpOffsetVX != 0,
+		DisplayWait:      b&quirkDisplayWait != 0,
+	}
+}
+
+// Snapshot serializes the full emulator state - memory, registers,
+// timers, stack, framebuffer, key matrix, XO-CHIP extras and quirks -
+// into a versioned binary blob suitable for Load.
+func (c8 *Chip8) Snapshot() ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+
+	write := func(v interface{}) {
+		if err != nil {
+			return
+		}
+		err = binary.Write(&buf, binary.LittleEndian, v)
+	}
+
+	write(snapshotMagic)
+	write(snapshotVersion)
+	write(c8.memory)
+	write(c8.v)
+	write(c8.i)
+	write(c8.pc)
+	write(c8.delayTimer)
+	write(c8.soundTimer)
+	write(c8.stack)
+	write(c8.sp)
+	write(c8.drawFlag)
+	write(uint16(c8.fb.width))
+	write(uint16(c8.fb.height))
+	write(c8.fb.planes[0])
+	write(c8.fb.planes[1])
+	write(c8.key)
+	write(c8.plane)
+	write(c8.rpl)
+	write(c8.audioPattern)
+	write(c8.pitch)
+	write(packQuirks(c8.quirks))
+
+	if err != nil {
+		return nil, fmt.Errorf("chip8: snapshot: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Load restores a Chip8 previously serialized with Snapshot. The
+// returned Chip8 has no display, keyboard or buzzer attached; to restore
+// state into a Chip8 that keeps its peripherals, use Restore.
+func Load(data []byte) (*Chip8, error) {
+	r := bytes.NewReader(data)
+
+	var magic [4]byte
+	var version uint16
+	if err := binary.Read(r, binary.LittleEndian, &magic); err != nil || magic != snapshotMagic {
+		return nil, ErrBadSnapshot
+	}
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return nil, ErrBadSnapshot
+	}
+	if version != snapshotVersion {
+		return nil, ErrSnapshotVersion
+	}
+
+	c8 := &Chip8{fb: newFramebuffer(), rand: mathRand{}}
+	var err error
+
+	read := func(v interface{}) {
+		if err != nil {
+			return
+		}
+		err = binary.Read(r, binary.LittleEndian, v)
+	}
+
+	read(&c8.memory)
+	read(&c8.v)
+	read(&c8.i)
+	read(&c8.pc)
+	read(&c8.delayTimer)
+	read(&c8.soundTimer)
+	read(&c8.stack)
+	read(&c8.sp)
+	read(&c8.drawFlag)
+
+	var width, height uint16
+	read(&width)
+	read(&height)
+	if err != nil {
+		return nil, fmt.Errorf("chip8: load: %w", err)
+	}
+	c8.fb.resize(int(width), int(height))
+
+	read(c8.fb.planes[0])
+	read(c8.fb.planes[1])
+	read(&c8.key)
+	read(&c8.plane)
+	read(&c8.rpl)
+	read(&c8.audioPattern)
+	read(&c8.pitch)
+
+	var packedQuirks byte
+	read(&packedQuirks)
+
+	if err != nil {
+		return nil, fmt.Errorf("chip8: load: %w", err)
+	}
+
+	c8.quirks = unpackQuirks(packedQuirks)
+	c8.vblankReady = true
+	c8.clockHz = defaultClockHz
+	return c8, nil
+}
+
+// Restore replaces c8's emulation state in place with a snapshot
+// previously written by Snapshot, preserving c8's existing display,
+// keyboard, buzzer, rand, clockHz and trapHandler rather than wiping
+// them the way assigning the result of Load over c8 would. This is what
+// a Debugger's ReverseStep uses to rewind without dropping the live
+// peripherals it was constructed with.
+func (c8 *Chip8) Restore(data []byte) error {
+	restored, err := Load(data)
+	if err != nil {
+		return err
+	}
+
+	display, keyboard, buzzer, rand := c8.display, c8.keyboard, c8.buzzer, c8.rand
+	clockHz, trapHandler := c8.clockHz, c8.trapHandler
+
+	*c8 = *restored
+
+	c8.display, c8.keyboard, c8.buzzer, c8.rand = display, keyboard, buzzer, rand
+	c8.clockHz, c8.trapHandler = clockHz, trapHandler
+	return nil
+}
+
+// SaveToFile writes a Snapshot of c8 to filepath.
+func (c8 *Chip8) SaveToFile(filepath string) error {
+	data, err := c8.Snapshot()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath, data, 0644)
+}
+
+// LoadFromFile reads a snapshot previously written by SaveToFile.
+func LoadFromFile(filepath string) (*Chip8, error) {
+	data, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	return Load(data)
+}
@@ -0,0 +1,125 @@
+package chip8
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// stubDisplay/stubKeyboard/stubBuzzer are no-op peripherals so tests can
+// construct a Chip8 without pulling in a real frontend.
+type stubDisplay struct{}
+
+func (stubDisplay) SetPixel(x, y int, on bool) {}
+func (stubDisplay) Refresh() error             { return nil }
+func (stubDisplay) Resize(width, height int)   {}
+
+type stubKeyboard struct{}
+
+func (stubKeyboard) IsDown(key byte) bool                      { return false }
+func (stubKeyboard) WaitKey(ctx context.Context) (byte, error) { return 0, nil }
+
+type stubBuzzer struct{}
+
+func (stubBuzzer) Start() {}
+func (stubBuzzer) Stop()  {}
+
+// counterROM repeatedly increments V0 and writes it to memory via FX55,
+// advancing I each time, so that both V-registers and memory diverge
+// from their initial state as cycles run.
+var counterROM = []byte{
+	0x60, 0x00, // 0x200: LD V0, 0x00
+	0xA3, 0x00, // 0x202: LD I, 0x300
+	0x70, 0x01, // 0x204: ADD V0, 1
+	0xF0, 0x55, // 0x206: LD [I], V0
+	0x12, 0x04, // 0x208: JP 0x204
+}
+
+func newTestChip8(t *testing.T, rom []byte) *Chip8 {
+	t.Helper()
+
+	f, err := ioutil.TempFile("", "chip8-rom-*.ch8")
+	if err != nil {
+		t.Fatalf("tempfile: %v", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(rom); err != nil {
+		t.Fatalf("write rom: %v", err)
+	}
+	f.Close()
+
+	c8, err := LoadROMFromFile(f.Name(), stubDisplay{}, stubKeyboard{}, stubBuzzer{}, nil, WithQuirks(ProfileChip8))
+	if err != nil {
+		t.Fatalf("LoadROMFromFile: %v", err)
+	}
+	return c8
+}
+
+func runCycles(t *testing.T, c8 *Chip8, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := c8.EmulateCycle(); err != nil {
+			t.Fatalf("EmulateCycle: %v", err)
+		}
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	const warmup = 50
+	const more = 50
+
+	live := newTestChip8(t, counterROM)
+	runCycles(t, live, warmup)
+
+	data, err := live.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	restored, err := Load(data)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	runCycles(t, live, more)
+	runCycles(t, restored, more)
+
+	if !bytes.Equal(live.memory[:], restored.memory[:]) {
+		t.Fatalf("memory diverged after restore")
+	}
+	if live.v != restored.v {
+		t.Fatalf("registers diverged after restore: live=%v restored=%v", live.v, restored.v)
+	}
+	if live.pc != restored.pc {
+		t.Fatalf("pc diverged after restore: live=%#x restored=%#x", live.pc, restored.pc)
+	}
+	if !bytes.Equal(live.fb.planes[0], restored.fb.planes[0]) {
+		t.Fatalf("gfx plane 0 diverged after restore")
+	}
+	if !bytes.Equal(live.fb.planes[1], restored.fb.planes[1]) {
+		t.Fatalf("gfx plane 1 diverged after restore")
+	}
+}
+
+func TestSnapshotRejectsBadMagic(t *testing.T) {
+	if _, err := Load([]byte("not a snapshot")); err != ErrBadSnapshot {
+		t.Fatalf("expected ErrBadSnapshot, got %v", err)
+	}
+}
+
+func TestSnapshotRejectsUnknownVersion(t *testing.T) {
+	live := newTestChip8(t, counterROM)
+	data, err := live.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	data[4] = 0xFF // version low byte, now bogus
+	data[5] = 0xFF
+
+	if _, err := Load(data); err != ErrSnapshotVersion {
+		t.Fatalf("expected ErrSnapshotVersion, got %v", err)
+	}
+}
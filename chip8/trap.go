@@ -0,0 +1,104 @@
+package chip8
+
+import "fmt"
+
+// TrapKind identifies the category of a TrapError.
+type TrapKind int
+
+// Trap kinds returned by EmulateCycle. A frontend can use Kind to
+// decide how to react - e.g. pausing and opening a debugger - instead
+// of the CPU panicking or silently corrupting state.
+const (
+	TrapMemOOB TrapKind = iota
+	TrapStackOverflow
+	TrapStackUnderflow
+	TrapUnknownOpcode
+	TrapInvalidSprite
+)
+
+func (k TrapKind) String() string {
+	switch k {
+	case TrapMemOOB:
+		return "memory access out of bounds"
+	case TrapStackOverflow:
+		return "stack overflow"
+	case TrapStackUnderflow:
+		return "stack underflow"
+	case TrapUnknownOpcode:
+		return "unknown opcode"
+	case TrapInvalidSprite:
+		return "invalid sprite"
+	default:
+		return "unknown trap"
+	}
+}
+
+// TrapError is returned by EmulateCycle when execution hits a condition
+// that would otherwise panic or silently corrupt state. PC and Opcode
+// describe where it happened so a debugger can be opened right there.
+type TrapError struct {
+	Kind   TrapKind
+	PC     uint16
+	Opcode uint16
+	Detail string
+}
+
+func (e *TrapError) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("chip8: %s at %#04x (opcode %#04x)", e.Kind, e.PC, e.Opcode)
+	}
+	return fmt.Sprintf("chip8: %s at %#04x (opcode %#04x): %s", e.Kind, e.PC, e.Opcode, e.Detail)
+}
+
+func (c8 *Chip8) trap(kind TrapKind, detail string) *TrapError {
+	return &TrapError{Kind: kind, PC: c8.pc, Opcode: c8.opcode, Detail: detail}
+}
+
+// memSize and stackSize bound the two fixed-size buffers EmulateCycle
+// indexes into directly; every access through them is checked rather
+// than trusted the way raw slice indexing would.
+const (
+	memSize   = 4096
+	stackSize = 16
+)
+
+// readMem reads a single byte, trapping instead of panicking if addr is
+// out of range.
+func (c8 *Chip8) readMem(addr uint16) (byte, error) {
+	if int(addr) >= memSize {
+		return 0, c8.trap(TrapMemOOB, fmt.Sprintf("read at %#04x", addr))
+	}
+	return c8.memory[addr], nil
+}
+
+// writeMem writes a single byte, trapping instead of panicking if addr
+// is out of range.
+func (c8 *Chip8) writeMem(addr uint16, v byte) error {
+	if int(addr) >= memSize {
+		return c8.trap(TrapMemOOB, fmt.Sprintf("write at %#04x", addr))
+	}
+	c8.memory[addr] = v
+	return nil
+}
+
+// pushStack pushes pc onto the call stack, trapping instead of
+// overflowing c8.stack if it's already full.
+func (c8 *Chip8) pushStack(pc uint16) error {
+	if c8.sp+1 >= stackSize {
+		return c8.trap(TrapStackOverflow, fmt.Sprintf("depth %d", c8.sp))
+	}
+	c8.sp++
+	c8.stack[c8.sp] = pc
+	return nil
+}
+
+// popStack pops and returns the top of the call stack, trapping instead
+// of underflowing c8.sp if it's already empty.
+func (c8 *Chip8) popStack() (uint16, error) {
+	if c8.sp == 0 {
+		return 0, c8.trap(TrapStackUnderflow, "")
+	}
+	v := c8.stack[c8.sp]
+	c8.sp--
+	return v, nil
+}
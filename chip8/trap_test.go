@@ -0,0 +1,74 @@
+package chip8
+
+import (
+	"errors"
+	"testing"
+)
+
+func expectTrap(t *testing.T, rom []byte, want TrapKind) {
+	t.Helper()
+
+	c8 := newTestChip8(t, rom)
+
+	var trapErr *TrapError
+	for i := 0; i < 32; i++ {
+		err := c8.EmulateCycle()
+		if err == nil {
+			continue
+		}
+		if !errors.As(err, &trapErr) {
+			t.Fatalf("EmulateCycle returned non-trap error: %v", err)
+		}
+		if trapErr.Kind != want {
+			t.Fatalf("trap kind = %v, want %v", trapErr.Kind, want)
+		}
+		return
+	}
+	t.Fatalf("expected a %v trap within 32 cycles, got none", want)
+}
+
+func TestTrapMemOOB(t *testing.T) {
+	rom := []byte{
+		0xAF, 0xFF, // 0x200: LD I, 0xFFF
+		0xF1, 0x55, // 0x202: LD [I], V0, V1 - writes I and I+1, the latter out of range
+	}
+	expectTrap(t, rom, TrapMemOOB)
+}
+
+func TestTrapStackOverflow(t *testing.T) {
+	rom := []byte{
+		0x22, 0x00, // 0x200: CALL 0x200 - recurses until the stack is full
+	}
+	expectTrap(t, rom, TrapStackOverflow)
+}
+
+func TestTrapStackUnderflow(t *testing.T) {
+	rom := []byte{
+		0x00, 0xEE, // 0x200: RET with nothing on the stack
+	}
+	expectTrap(t, rom, TrapStackUnderflow)
+}
+
+func TestTrapUnknownOpcode(t *testing.T) {
+	rom := []byte{
+		0x90, 0x01, // 0x200: not a valid 9XY0
+	}
+	expectTrap(t, rom, TrapUnknownOpcode)
+}
+
+func TestTrapUnknownOpcode8XYn(t *testing.T) {
+	rom := []byte{
+		0x80, 0x08, // 0x200: not a valid 8XY0-8XY7/8XYE sub-opcode
+	}
+	expectTrap(t, rom, TrapUnknownOpcode)
+}
+
+func TestTrapInvalidSprite(t *testing.T) {
+	rom := []byte{
+		0xAF, 0xFF, // 0x200: LD I, 0xFFF
+		0x60, 0x00, // 0x202: LD V0, 0
+		0x61, 0x00, // 0x204: LD V1, 0
+		0xD0, 0x1F, // 0x206: DRW V0, V1, 15 - sprite data runs past memory
+	}
+	expectTrap(t, rom, TrapInvalidSprite)
+}